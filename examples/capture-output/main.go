@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/component"
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+func main() {
+	fr, err := frame.New(frame.Config{
+		TrailOnRemove:  true,
+		PositionPolicy: frame.PolicyFloatForward,
+		CaptureOutput:  true,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	line, _ := fr.Append()
+	spinner := component.NewSpinner(line, nil, "working")
+
+	// output from other code lands above the frame instead of in the middle of it
+	for idx := 0; idx < 5; idx++ {
+		time.Sleep(500 * time.Millisecond)
+		log.Printf("log message %d", idx)
+		fmt.Printf("printed message %d\n", idx)
+	}
+
+	spinner.Success("done")
+	fr.Remove(line)
+
+	fr.Close()
+	frame.Close()
+}
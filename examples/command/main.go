@@ -0,0 +1,29 @@
+package main
+
+import (
+	"os/exec"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+	"github.com/wagoodman/jotframe/pkg/recipe"
+)
+
+func main() {
+	fr, err := frame.New(frame.Config{
+		TrailOnRemove:  true,
+		PositionPolicy: frame.PolicyFloatForward,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	recipe.RunCommand(fr, exec.Command("sh", "-c", "for i in 1 2 3 4 5; do echo step $i; sleep 1; done"), recipe.CommandOptions{
+		TailRows: 3,
+	})
+
+	recipe.RunCommand(fr, exec.Command("sh", "-c", "echo compiling; sleep 1; echo 'error: missing semicolon' >&2; exit 2"), recipe.CommandOptions{
+		Title: "compile",
+	})
+
+	fr.Close()
+	frame.Close()
+}
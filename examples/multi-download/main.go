@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/component"
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+// slowReader simulates a download by trickling out zeros
+type slowReader struct {
+	remaining int
+	chunk     int
+}
+
+func (r *slowReader) Read(buff []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(50 * time.Millisecond)
+	size := r.chunk
+	if size > len(buff) {
+		size = len(buff)
+	}
+	if size > r.remaining {
+		size = r.remaining
+	}
+	r.remaining -= size
+	return size, nil
+}
+
+func main() {
+	rand.Seed(time.Now().Unix())
+
+	fr, err := frame.New(frame.Config{
+		TrailOnRemove:  true,
+		PositionPolicy: frame.PolicyFloatForward,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	var wg sync.WaitGroup
+	for idx := 0; idx < 5; idx++ {
+		size := (rand.Intn(8) + 2) * 1024 * 1024
+		line, _ := fr.Append()
+		reader := component.ProxyReader(line, &slowReader{remaining: size, chunk: rand.Intn(64*1024) + 32*1024}, int64(size))
+		reader.Bar().SetLabel(fmt.Sprintf("file-%d.tar.gz", idx))
+
+		wg.Add(1)
+		go func(line *frame.Line) {
+			defer wg.Done()
+			io.Copy(ioutil.Discard, reader)
+			fr.Remove(line)
+		}(line)
+	}
+	wg.Wait()
+
+	fr.Close()
+	frame.Close()
+}
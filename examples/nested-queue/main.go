@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+	"github.com/wagoodman/jotframe/pkg/recipe"
+)
+
+func compile(pkg, file string) recipe.Worker {
+	return recipe.WorkerFunc(func(ctx context.Context, line *frame.Line) error {
+		line.WriteString(fmt.Sprintf("compiling %s", file))
+		time.Sleep(time.Duration(rand.Intn(800)+200) * time.Millisecond)
+		return nil
+	})
+}
+
+func main() {
+	rand.Seed(time.Now().Unix())
+
+	wq := recipe.NewWorkQueue(4)
+	for _, pkg := range []string{"pkg/frame", "pkg/component", "pkg/recipe"} {
+		pkg := pkg
+		wq.AddWork(recipe.WorkerFunc(func(ctx context.Context, line *frame.Line) error {
+			line.WriteString(fmt.Sprintf("%s: compiling...", pkg))
+
+			files := recipe.NewChildQueue(line)
+			for idx := 0; idx < 4; idx++ {
+				files.AddWork(compile(pkg, fmt.Sprintf("%s/file%d.go", pkg, idx)))
+			}
+			_, err := files.Work(ctx)
+
+			line.WriteString(fmt.Sprintf("%s: done", pkg))
+			return err
+		}))
+	}
+
+	_, err := wq.Work(context.Background())
+	frame.Close()
+
+	if err != nil {
+		fmt.Println(err)
+	}
+}
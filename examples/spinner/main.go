@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/component"
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+func main() {
+	rand.Seed(time.Now().Unix())
+
+	fr, err := frame.New(frame.Config{
+		TrailOnRemove:  true,
+		PositionPolicy: frame.PolicyFloatForward,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	styles := []component.SpinnerStyle{component.SpinnerDots, component.SpinnerLine, component.SpinnerBraille}
+
+	var wg sync.WaitGroup
+	for idx := 0; idx < 6; idx++ {
+		line, _ := fr.Append()
+		spinner := component.NewSpinner(line, styles[idx%len(styles)], fmt.Sprintf("task %d", idx))
+
+		wg.Add(1)
+		go func(idx int, line *frame.Line) {
+			defer wg.Done()
+			time.Sleep(time.Duration(rand.Intn(4000)+1000) * time.Millisecond)
+			if idx%4 == 3 {
+				spinner.Fail(fmt.Sprintf("task %d failed", idx))
+			} else {
+				spinner.Success(fmt.Sprintf("task %d done", idx))
+			}
+			fr.Remove(line)
+		}(idx, line)
+	}
+	wg.Wait()
+
+	fr.Close()
+	frame.Close()
+}
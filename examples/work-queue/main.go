@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+	"github.com/wagoodman/jotframe/pkg/recipe"
+)
+
+func main() {
+	rand.Seed(time.Now().Unix())
+
+	wq := recipe.NewWorkQueue(3)
+	wq.Summary = recipe.SummaryFooter
+	wq.HandleInterrupt = true
+
+	for idx := 0; idx < 10; idx++ {
+		idx := idx
+		wq.AddWork(recipe.WorkerFunc(func(ctx context.Context, line *frame.Line) error {
+			line.WriteString(fmt.Sprintf("task %d: working...", idx))
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(rand.Intn(2000)+500) * time.Millisecond):
+			}
+			if idx%5 == 4 {
+				line.WriteString(fmt.Sprintf("task %d: failed", idx))
+				return fmt.Errorf("task %d failed", idx)
+			}
+			line.WriteString(fmt.Sprintf("task %d: done", idx))
+			return nil
+		}))
+	}
+
+	_, err := wq.Work(context.Background())
+	frame.Close()
+
+	if err != nil {
+		fmt.Println(err)
+	}
+}
@@ -0,0 +1,101 @@
+package component
+
+import (
+	"sync"
+	"time"
+)
+
+// clockInterval is how often animated components are refreshed
+const clockInterval = 100 * time.Millisecond
+
+// sharedClock drives all animated components from a single goroutine (instead of one per line)
+var sharedClock = newClock(clockInterval)
+
+// OnTick calls the given function on every tick of the clock shared by all animated components (so that live
+// displays outside of this package refresh together with them) until the returned function is called. A tick
+// already in progress may still call the function once after it has been stopped.
+func OnTick(onTick func(now time.Time)) (stop func()) {
+	return sharedClock.subscribe(onTick)
+}
+
+type clock struct {
+	interval    time.Duration
+	ticker      func(interval time.Duration) (ticks <-chan time.Time, stop func())
+	lock        sync.Mutex
+	subscribers map[int]func(time.Time)
+	nextID      int
+	stop        chan struct{}
+}
+
+func newClock(interval time.Duration) *clock {
+	return &clock{
+		interval:    interval,
+		ticker:      newTicker,
+		subscribers: make(map[int]func(time.Time)),
+	}
+}
+
+func newTicker(interval time.Duration) (<-chan time.Time, func()) {
+	ticker := time.NewTicker(interval)
+	return ticker.C, ticker.Stop
+}
+
+// subscribe registers a function to be called on every tick. The clock only runs while there is at least one
+// subscriber. The returned function unsubscribes (and is safe to call more than once). Subscribers are called
+// outside of the clock lock, so a tick that is already being delivered may still call a subscriber once after it
+// has unsubscribed (unsubscribing does not wait for it, since subscribers may unsubscribe from within a tick).
+func (c *clock) subscribe(onTick func(now time.Time)) func() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	id := c.nextID
+	c.nextID++
+	c.subscribers[id] = onTick
+
+	if c.stop == nil {
+		c.stop = make(chan struct{})
+		go c.run(c.stop)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.unsubscribe(id)
+		})
+	}
+}
+
+func (c *clock) unsubscribe(id int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.subscribers, id)
+	if len(c.subscribers) == 0 && c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+}
+
+func (c *clock) run(stop chan struct{}) {
+	ticks, stopTicker := c.ticker(c.interval)
+	defer stopTicker()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticks:
+			// subscribers write to lines (which takes the screen lock), don't hold the clock lock meanwhile
+			c.lock.Lock()
+			subscribers := make([]func(time.Time), 0, len(c.subscribers))
+			for _, onTick := range c.subscribers {
+				subscribers = append(subscribers, onTick)
+			}
+			c.lock.Unlock()
+
+			for _, onTick := range subscribers {
+				onTick(now)
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package component
+
+import (
+	"testing"
+	"time"
+)
+
+// manualClock returns a clock that only ticks when a time is sent on the returned channel.
+func manualClock() (*clock, chan time.Time) {
+	ticks := make(chan time.Time)
+	c := newClock(time.Hour)
+	c.ticker = func(time.Duration) (<-chan time.Time, func()) {
+		return ticks, func() {}
+	}
+	return c, ticks
+}
+
+func receiveTick(t *testing.T, ticks chan time.Time) {
+	t.Helper()
+	select {
+	case <-ticks:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("clock.subscribe(): expected the subscriber to be ticked")
+	}
+}
+
+func Test_Clock_Subscribe(t *testing.T) {
+	c, ticks := manualClock()
+
+	first := make(chan time.Time, 10)
+	second := make(chan time.Time, 10)
+	unsubscribeFirst := c.subscribe(func(now time.Time) { first <- now })
+	unsubscribeSecond := c.subscribe(func(now time.Time) { second <- now })
+
+	ticks <- time.Now()
+	receiveTick(t, first)
+	receiveTick(t, second)
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+
+	// the second subscriber being ticked means the whole tick has been delivered
+	ticks <- time.Now()
+	receiveTick(t, second)
+	if len(first) != 0 {
+		t.Errorf("clock.subscribe(): expected no ticks after unsubscribing")
+	}
+
+	unsubscribeSecond()
+	c.lock.Lock()
+	running := c.stop != nil
+	c.lock.Unlock()
+	if running {
+		t.Errorf("clock.subscribe(): expected the clock to stop without subscribers")
+	}
+}
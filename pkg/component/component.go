@@ -0,0 +1,7 @@
+package component
+
+// lineWriter is the part of a frame.Line that components draw to
+type lineWriter interface {
+	WriteString(str string) error
+	WriteStringAndClose(str string) (int, error)
+}
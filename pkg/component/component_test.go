@@ -0,0 +1,53 @@
+package component
+
+import (
+	"sync"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+// standaloneEvents receives (and discards) the screen events of lines that are not part of a frame
+var standaloneEvents = func() chan frame.ScreenEvent {
+	events := make(chan frame.ScreenEvent, 100)
+	go func() {
+		for range events {
+		}
+	}()
+	return events
+}()
+
+// newStandaloneLine returns a line that is not part of a frame.
+func newStandaloneLine() *frame.Line {
+	return frame.NewLine(0, standaloneEvents)
+}
+
+// testLine records everything written by a component
+type testLine struct {
+	lock   sync.Mutex
+	writes []string
+	closed bool
+}
+
+func (line *testLine) WriteString(str string) error {
+	line.lock.Lock()
+	defer line.lock.Unlock()
+	line.writes = append(line.writes, str)
+	return nil
+}
+
+func (line *testLine) WriteStringAndClose(str string) (int, error) {
+	line.lock.Lock()
+	defer line.lock.Unlock()
+	line.writes = append(line.writes, str)
+	line.closed = true
+	return len(str), nil
+}
+
+func (line *testLine) last() string {
+	line.lock.Lock()
+	defer line.lock.Unlock()
+	if len(line.writes) == 0 {
+		return ""
+	}
+	return line.writes[len(line.writes)-1]
+}
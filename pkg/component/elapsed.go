@@ -0,0 +1,202 @@
+package component
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+// Elapsed renders a message followed by the time elapsed since it was started (e.g. "building 1m23s"). When the
+// line is removed, the trail entry receives the final elapsed value (passed through any trail formatter already
+// applied to the line).
+type Elapsed struct {
+	*durationDisplay
+}
+
+// Countdown renders a message followed by the time remaining until a deadline (e.g. "retrying in 5s"). The
+// countdown stops by itself once the deadline has passed.
+type Countdown struct {
+	*durationDisplay
+	deadline time.Time
+}
+
+// durationDisplay draws a duration that is refreshed on the shared clock.
+type durationDisplay struct {
+	line    lineWriter
+	message string
+	format  func(time.Duration) string
+	value   func(now time.Time) time.Duration
+	last    string
+	final   bool
+	stopped time.Time
+	done    bool
+	closed  bool
+	lock    sync.Mutex
+	stop    func()
+}
+
+// NewElapsed starts drawing the time elapsed from now on the given line. The message may contain a "%s"
+// placeholder for the duration, otherwise the duration is drawn after the message.
+func NewElapsed(line *frame.Line, message string) *Elapsed {
+	start := time.Now()
+	display := newDurationDisplay(line, message, func(now time.Time) time.Duration {
+		return now.Sub(start)
+	})
+	display.start(line)
+	return &Elapsed{display}
+}
+
+// NewCountdown starts drawing the time remaining until the given deadline on the given line. The message may
+// contain a "%s" placeholder for the duration, otherwise the duration is drawn after the message.
+func NewCountdown(line *frame.Line, message string, deadline time.Time) *Countdown {
+	countdown := newCountdown(line, message, deadline)
+	countdown.start(line)
+	return countdown
+}
+
+func newCountdown(line lineWriter, message string, deadline time.Time) *Countdown {
+	countdown := &Countdown{deadline: deadline}
+	countdown.durationDisplay = newDurationDisplay(line, message, func(now time.Time) time.Duration {
+		remaining := countdown.deadline.Sub(now)
+		if remaining < 0 {
+			return 0
+		}
+		return remaining
+	})
+	countdown.final = true
+	return countdown
+}
+
+// Restart counts down to a new deadline, resuming the countdown if it has stopped (so that one countdown can be
+// used for several waits on the same line). A countdown on a closed line stays stopped.
+func (countdown *Countdown) Restart(deadline time.Time) {
+	countdown.lock.Lock()
+	if countdown.closed {
+		countdown.lock.Unlock()
+		return
+	}
+	countdown.deadline = deadline
+	// the line may have been written to since the countdown was last drawn
+	countdown.last = ""
+	if countdown.done {
+		countdown.done = false
+		countdown.stop = sharedClock.subscribe(countdown.draw)
+	}
+	countdown.lock.Unlock()
+
+	countdown.draw(time.Now())
+}
+
+func newDurationDisplay(line lineWriter, message string, value func(time.Time) time.Duration) *durationDisplay {
+	return &durationDisplay{
+		line:    line,
+		message: message,
+		format:  FormatDuration,
+		value:   value,
+		stop:    func() {},
+	}
+}
+
+func (display *durationDisplay) start(line *frame.Line) {
+	display.draw(time.Now())
+
+	display.stop = sharedClock.subscribe(display.draw)
+
+	line.OnClose(func(*frame.Line) {
+		display.lock.Lock()
+		display.closed = true
+		display.lock.Unlock()
+		display.Stop()
+	})
+
+	// the trail should reflect the duration at the time of removal (not the last tick), formatted as it would
+	// have been otherwise
+	formatter := line.TrailFormatter()
+	line.SetTrailFormatter(func(entry frame.TrailEntry) (string, bool) {
+		display.lock.Lock()
+		entry.Content = display.render(display.now())
+		display.lock.Unlock()
+
+		if formatter == nil {
+			return entry.Content, true
+		}
+		return formatter(entry)
+	})
+}
+
+// SetMessage replaces the message drawn with the duration.
+func (display *durationDisplay) SetMessage(message string) {
+	display.lock.Lock()
+	display.message = message
+	display.lock.Unlock()
+}
+
+// SetFormat replaces how the duration is rendered (FormatDuration by default).
+func (display *durationDisplay) SetFormat(format func(time.Duration) string) {
+	display.lock.Lock()
+	display.format = format
+	display.lock.Unlock()
+}
+
+// Duration returns the currently displayed duration (frozen once stopped).
+func (display *durationDisplay) Duration() time.Duration {
+	display.lock.Lock()
+	defer display.lock.Unlock()
+	return display.value(display.now())
+}
+
+// Stop freezes the displayed duration without writing to or closing the line.
+func (display *durationDisplay) Stop() {
+	display.lock.Lock()
+	if !display.done {
+		display.done = true
+		display.stopped = time.Now()
+	}
+	stop := display.stop
+	display.lock.Unlock()
+
+	stop()
+}
+
+// now is the reference time for the duration (which no longer advances once stopped).
+func (display *durationDisplay) now() time.Time {
+	if display.done {
+		return display.stopped
+	}
+	return time.Now()
+}
+
+func (display *durationDisplay) draw(now time.Time) {
+	display.lock.Lock()
+	if display.done {
+		display.lock.Unlock()
+		return
+	}
+	value := display.value(now)
+	contents := display.render(now)
+	changed := contents != display.last
+	display.last = contents
+	display.lock.Unlock()
+
+	if changed {
+		display.line.WriteString(contents)
+	}
+
+	// a countdown has nothing left to show once it reaches zero
+	if display.final && value <= 0 {
+		display.Stop()
+	}
+}
+
+func (display *durationDisplay) render(now time.Time) string {
+	value := display.format(display.value(now))
+	if strings.Contains(display.message, "%s") {
+		return strings.Replace(display.message, "%s", value, 1)
+	}
+	if display.message == "" {
+		return value
+	}
+	return display.message + " " + value
+}
@@ -0,0 +1,135 @@
+package component
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+func Test_Elapsed_render(t *testing.T) {
+	start := time.Now()
+	display := newDurationDisplay(nil, "building", func(now time.Time) time.Duration {
+		return now.Sub(start)
+	})
+
+	tables := []struct {
+		message  string
+		offset   time.Duration
+		expected string
+	}{
+		{"building", 83 * time.Second, "building 1m23s"},
+		{"step took %s", 3 * time.Minute, "step took 3m00s"},
+		{"", 5 * time.Second, "5s"},
+	}
+
+	for _, table := range tables {
+		display.SetMessage(table.message)
+		if actual := display.render(start.Add(table.offset)); actual != table.expected {
+			t.Errorf("durationDisplay.render(): expected '%s', got '%s'", table.expected, actual)
+		}
+	}
+
+	display.SetFormat(func(d time.Duration) string { return d.String() })
+	if actual := display.render(start.Add(1500 * time.Millisecond)); actual != "1.5s" {
+		t.Errorf("durationDisplay.SetFormat(): expected '1.5s', got '%s'", actual)
+	}
+}
+
+func Test_Countdown_draw(t *testing.T) {
+	line := &testLine{}
+	deadline := time.Now().Add(2 * time.Second)
+	display := newDurationDisplay(line, "retrying in %s…", func(now time.Time) time.Duration {
+		remaining := deadline.Sub(now)
+		if remaining < 0 {
+			return 0
+		}
+		return remaining
+	})
+	display.final = true
+
+	display.draw(deadline.Add(-1500 * time.Millisecond))
+	display.draw(deadline.Add(-1400 * time.Millisecond))
+	display.draw(deadline.Add(-500 * time.Millisecond))
+	display.draw(deadline.Add(time.Second))
+	display.draw(deadline.Add(2 * time.Second))
+
+	// unchanged text is not re-written and the countdown stops at zero
+	expected := []string{"retrying in 1s…", "retrying in 0s…"}
+	if len(line.writes) != len(expected) {
+		t.Fatalf("durationDisplay.draw(): expected writes %v, got %v", expected, line.writes)
+	}
+	for idx, value := range expected {
+		if line.writes[idx] != value {
+			t.Errorf("durationDisplay.draw(): expected '%s', got '%s'", value, line.writes[idx])
+		}
+	}
+
+	if !display.done {
+		t.Errorf("durationDisplay.draw(): expected the countdown to stop at the deadline")
+	}
+}
+
+func Test_Countdown_Restart(t *testing.T) {
+	line := &testLine{}
+	countdown := newCountdown(line, "retrying in %s", time.Now())
+	countdown.draw(time.Now())
+
+	if !countdown.done || len(line.writes) != 1 || line.writes[0] != "retrying in 0s" {
+		t.Fatalf("Countdown: expected the countdown to stop at the deadline, got %v", line.writes)
+	}
+
+	countdown.SetMessage("retrying again in %s")
+	countdown.Restart(time.Now().Add(90 * time.Minute))
+	defer countdown.Stop()
+
+	if countdown.done || !strings.HasPrefix(line.last(), "retrying again in 1h29m") {
+		t.Errorf("Countdown.Restart(): expected the countdown to resume, got '%s'", line.last())
+	}
+
+	// the same text is drawn again, since the line may have been written to meanwhile
+	countdown.Restart(time.Now().Add(time.Hour))
+	if len(line.writes) != 3 {
+		t.Errorf("Countdown.Restart(): expected the countdown to be redrawn, got %v", line.writes)
+	}
+
+	countdown.Stop()
+	countdown.closed = true
+	countdown.Restart(time.Now().Add(time.Hour))
+	if !countdown.done {
+		t.Errorf("Countdown.Restart(): expected a countdown on a closed line to stay stopped")
+	}
+}
+
+func Test_Elapsed_Stop(t *testing.T) {
+	start := time.Now().Add(-time.Minute)
+	display := newDurationDisplay(&testLine{}, "", func(now time.Time) time.Duration {
+		return now.Sub(start)
+	})
+
+	display.Stop()
+	frozen := display.Duration()
+	time.Sleep(10 * time.Millisecond)
+
+	if display.Duration() != frozen {
+		t.Errorf("durationDisplay.Stop(): expected the duration to be frozen")
+	}
+}
+
+func Test_Elapsed_trail(t *testing.T) {
+	line := newStandaloneLine()
+	line.SetTrailFormatter(func(entry frame.TrailEntry) (string, bool) {
+		return "✔ " + entry.Content, true
+	})
+
+	elapsed := NewElapsed(line, "build")
+	elapsed.SetFormat(func(time.Duration) string { return "1m" })
+	elapsed.Stop()
+
+	// the final duration replaces whatever was last drawn, and still goes through the existing formatter
+	actual, keep := line.TrailFormatter()(frame.TrailEntry{Content: "build 0s"})
+	if !keep || actual != "✔ build 1m" {
+		t.Errorf("Elapsed: expected trail entry '✔ build 1m', got '%s'", actual)
+	}
+}
@@ -0,0 +1,55 @@
+package component
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatDuration renders a duration in a compact form such as "45s", "1m23s" or "2h05m09s".
+func FormatDuration(duration time.Duration) string {
+	if duration < 0 {
+		duration = 0
+	}
+	duration = duration.Truncate(time.Second)
+
+	hours := int(duration / time.Hour)
+	minutes := int(duration%time.Hour) / int(time.Minute)
+	seconds := int(duration%time.Minute) / int(time.Second)
+
+	switch {
+	case hours > 0:
+		return fmt.Sprintf("%dh%02dm%02ds", hours, minutes, seconds)
+	case minutes > 0:
+		return fmt.Sprintf("%dm%02ds", minutes, seconds)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
+
+// humanizeBytes renders a byte count with binary units (e.g. "1.5 MiB").
+func humanizeBytes(value float64) string {
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	idx := 0
+	for value >= 1024 && idx < len(units)-1 {
+		value /= 1024
+		idx++
+	}
+	if idx == 0 {
+		return fmt.Sprintf("%.0f %s", value, units[idx])
+	}
+	return fmt.Sprintf("%.1f %s", value, units[idx])
+}
+
+// humanizeCount renders an item count with metric suffixes (e.g. "12.3k").
+func humanizeCount(value float64) string {
+	units := []string{"", "k", "M", "G", "T"}
+	idx := 0
+	for value >= 1000 && idx < len(units)-1 {
+		value /= 1000
+		idx++
+	}
+	if idx == 0 {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return fmt.Sprintf("%.1f%s", value, units[idx])
+}
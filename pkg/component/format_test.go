@@ -0,0 +1,48 @@
+package component
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_FormatDuration(t *testing.T) {
+	tables := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{-time.Second, "0s"},
+		{0, "0s"},
+		{1500 * time.Millisecond, "1s"},
+		{45 * time.Second, "45s"},
+		{83 * time.Second, "1m23s"},
+		{2*time.Hour + 5*time.Minute + 9*time.Second, "2h05m09s"},
+	}
+
+	for _, table := range tables {
+		if actual := FormatDuration(table.duration); actual != table.expected {
+			t.Errorf("FormatDuration(%v): expected '%s', got '%s'", table.duration, table.expected, actual)
+		}
+	}
+}
+
+func Test_humanize(t *testing.T) {
+	tables := []struct {
+		value         float64
+		expectedBytes string
+		expectedCount string
+	}{
+		{0, "0 B", "0"},
+		{999, "999 B", "999"},
+		{1536, "1.5 KiB", "1.5k"},
+		{3 * 1024 * 1024, "3.0 MiB", "3.1M"},
+	}
+
+	for _, table := range tables {
+		if actual := humanizeBytes(table.value); actual != table.expectedBytes {
+			t.Errorf("humanizeBytes(%v): expected '%s', got '%s'", table.value, table.expectedBytes, actual)
+		}
+		if actual := humanizeCount(table.value); actual != table.expectedCount {
+			t.Errorf("humanizeCount(%v): expected '%s', got '%s'", table.value, table.expectedCount, actual)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package component
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+// LogTail shows the most recent lines of a stream of output in a fixed window of lines nested under a title
+// line, with older lines scrolling up and out of the window (similar to `docker build`). All output is retained
+// so that it can be shown in full if the stream ends with an error.
+type LogTail struct {
+	title  parentLine
+	rows   []*frame.Line
+	size   int
+	drawn  []string
+	buffer tailBuffer
+	closed bool
+	lock   sync.Mutex
+}
+
+// parentLine is the part of a frame.Line that the window is nested under
+type parentLine interface {
+	AppendChild() (*frame.Line, error)
+}
+
+// NewLogTail adds a window of the given number of lines under the title line. The title line itself is left to
+// the caller to write to.
+func NewLogTail(title *frame.Line, rows int) (*LogTail, error) {
+	return newLogTail(title, rows)
+}
+
+func newLogTail(title parentLine, rows int) (*LogTail, error) {
+	if rows < 1 {
+		return nil, fmt.Errorf("log tail must have at least one row")
+	}
+
+	tail := &LogTail{
+		title: title,
+		size:  rows,
+	}
+
+	for idx := 0; idx < rows; idx++ {
+		if err := tail.addRow(); err != nil {
+			return nil, err
+		}
+	}
+	return tail, nil
+}
+
+// Write adds output to the window. Output is split on newlines; an incomplete trailing line is held back until it
+// is completed (or the tail is closed).
+func (tail *LogTail) Write(buff []byte) (int, error) {
+	tail.lock.Lock()
+	defer tail.lock.Unlock()
+
+	if tail.closed {
+		return 0, fmt.Errorf("log tail is closed")
+	}
+
+	if tail.buffer.write(buff) {
+		tail.draw()
+	}
+	return len(buff), nil
+}
+
+// ReadFrom copies all output from the given reader into the window until io.EOF (which is not returned as an
+// error). The tail is not closed afterwards.
+func (tail *LogTail) ReadFrom(reader io.Reader) (int64, error) {
+	var total int64
+	buff := make([]byte, 32*1024)
+	for {
+		numBytes, err := reader.Read(buff)
+		if numBytes > 0 {
+			written, writeErr := tail.Write(buff[:numBytes])
+			total += int64(written)
+			if writeErr != nil {
+				return total, writeErr
+			}
+		}
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// Output returns every complete line written to the tail so far.
+func (tail *LogTail) Output() []string {
+	tail.lock.Lock()
+	defer tail.lock.Unlock()
+
+	output := make([]string, len(tail.buffer.lines))
+	copy(output, tail.buffer.lines)
+	return output
+}
+
+// Close ends the stream. Without an error the window is removed, leaving only the title line. With an error the
+// window is grown to show the full output instead.
+func (tail *LogTail) Close(cause error) error {
+	tail.lock.Lock()
+	defer tail.lock.Unlock()
+
+	if tail.closed {
+		return fmt.Errorf("log tail is already closed")
+	}
+	tail.closed = true
+	tail.buffer.flush()
+
+	if cause == nil {
+		for _, row := range tail.rows {
+			if err := row.Remove(); err != nil {
+				return err
+			}
+		}
+		tail.rows = nil
+		return nil
+	}
+
+	for len(tail.rows) < len(tail.buffer.lines) {
+		if err := tail.addRow(); err != nil {
+			return err
+		}
+	}
+	tail.size = len(tail.rows)
+	tail.draw()
+	return nil
+}
+
+func (tail *LogTail) addRow() error {
+	row, err := tail.title.AppendChild()
+	if err != nil {
+		return err
+	}
+	// the window is a live view of the output, not something that belongs in the trail
+	row.SetTrailFormatter(func(frame.TrailEntry) (string, bool) {
+		return "", false
+	})
+	tail.rows = append(tail.rows, row)
+	tail.drawn = append(tail.drawn, "")
+	return nil
+}
+
+// draw writes the latest output to the window, skipping rows that have not changed.
+func (tail *LogTail) draw() {
+	window := tail.buffer.tail(tail.size)
+	for idx, row := range tail.rows {
+		contents := ""
+		if idx < len(window) {
+			contents = window[idx]
+		}
+		if contents == tail.drawn[idx] {
+			continue
+		}
+		tail.drawn[idx] = contents
+		row.WriteString(contents)
+	}
+}
+
+// tailBuffer splits a stream of output into lines.
+type tailBuffer struct {
+	lines   []string
+	partial []byte
+}
+
+// write adds output to the buffer and returns true if any lines were completed.
+func (buffer *tailBuffer) write(buff []byte) bool {
+	completed := false
+	for {
+		idx := bytes.IndexByte(buff, '\n')
+		if idx < 0 {
+			buffer.partial = append(buffer.partial, buff...)
+			return completed
+		}
+		buffer.partial = append(buffer.partial, buff[:idx]...)
+		buffer.complete()
+		completed = true
+		buff = buff[idx+1:]
+	}
+}
+
+// flush completes any trailing partial line.
+func (buffer *tailBuffer) flush() {
+	if len(buffer.partial) > 0 {
+		buffer.complete()
+	}
+}
+
+func (buffer *tailBuffer) complete() {
+	buffer.lines = append(buffer.lines, strings.TrimRight(string(buffer.partial), "\r"))
+	buffer.partial = buffer.partial[:0]
+}
+
+// tail returns up to the last n complete lines.
+func (buffer *tailBuffer) tail(n int) []string {
+	if len(buffer.lines) <= n {
+		return buffer.lines
+	}
+	return buffer.lines[len(buffer.lines)-n:]
+}
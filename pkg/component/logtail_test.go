@@ -0,0 +1,134 @@
+package component
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+// testParent hands out standalone child lines that are not drawn anywhere
+type testParent struct {
+	children []*frame.Line
+}
+
+func (parent *testParent) AppendChild() (*frame.Line, error) {
+	line := newStandaloneLine()
+	parent.children = append(parent.children, line)
+	return line, nil
+}
+
+// windowOf returns the contents of every row in the window of a log tail.
+func windowOf(tail *LogTail) []string {
+	var contents []string
+	for _, row := range tail.rows {
+		contents = append(contents, row.Contents())
+	}
+	return contents
+}
+
+func Test_tailBuffer_write(t *testing.T) {
+	var buffer tailBuffer
+
+	if buffer.write([]byte("Step 1/3 : FROM")) {
+		t.Errorf("tailBuffer.write(): expected a partial line not to complete")
+	}
+
+	if !buffer.write([]byte(" alpine\r\nStep 2/3 : RUN make\n\nStep 3")) {
+		t.Errorf("tailBuffer.write(): expected lines to complete")
+	}
+
+	expected := []string{"Step 1/3 : FROM alpine", "Step 2/3 : RUN make", ""}
+	if !reflect.DeepEqual(buffer.lines, expected) {
+		t.Errorf("tailBuffer.write(): expected %q, got %q", expected, buffer.lines)
+	}
+
+	buffer.flush()
+	buffer.flush()
+	if last := buffer.lines[len(buffer.lines)-1]; last != "Step 3" || len(buffer.lines) != 4 {
+		t.Errorf("tailBuffer.flush(): expected the partial line once, got %q", buffer.lines)
+	}
+}
+
+func Test_tailBuffer_tail(t *testing.T) {
+	buffer := tailBuffer{lines: []string{"a", "b", "c", "d"}}
+
+	tables := []struct {
+		n        int
+		expected []string
+	}{
+		{2, []string{"c", "d"}},
+		{4, []string{"a", "b", "c", "d"}},
+		{6, []string{"a", "b", "c", "d"}},
+	}
+
+	for _, table := range tables {
+		if actual := buffer.tail(table.n); !reflect.DeepEqual(actual, table.expected) {
+			t.Errorf("tailBuffer.tail(%d): expected %q, got %q", table.n, table.expected, actual)
+		}
+	}
+}
+
+func Test_LogTail_Write(t *testing.T) {
+	parent := &testParent{}
+	tail, err := newLogTail(parent, 3)
+	if err != nil {
+		t.Fatalf("NewLogTail(): expected no error, got %v", err)
+	}
+	if len(parent.children) != 3 {
+		t.Fatalf("NewLogTail(): expected 3 rows under the title, got %d", len(parent.children))
+	}
+
+	tail.Write([]byte("Step 1/4\nStep 2/4\n"))
+	if actual, expected := windowOf(tail), []string{"Step 1/4", "Step 2/4", ""}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("LogTail.Write(): expected window %q, got %q", expected, actual)
+	}
+
+	// older lines scroll up and out of the window
+	tail.ReadFrom(strings.NewReader("Step 3/4\nStep 4/4\nbuil"))
+	if actual, expected := windowOf(tail), []string{"Step 2/4", "Step 3/4", "Step 4/4"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("LogTail.ReadFrom(): expected window %q, got %q", expected, actual)
+	}
+
+	if actual, expected := tail.Output(), []string{"Step 1/4", "Step 2/4", "Step 3/4", "Step 4/4"}; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("LogTail.Output(): expected %q, got %q", expected, actual)
+	}
+
+	if _, err := newLogTail(parent, 0); err == nil {
+		t.Errorf("NewLogTail(): expected an error for an empty window")
+	}
+}
+
+func Test_LogTail_Close(t *testing.T) {
+	tables := []struct {
+		name     string
+		cause    error
+		expected []string
+	}{
+		// the window collapses, leaving only the title line
+		{"success", nil, nil},
+		// the window grows to show the full output (including the partial last line)
+		{"failure", fmt.Errorf("exit status 1"), []string{"a", "b", "c", "d", "partial"}},
+	}
+
+	for _, table := range tables {
+		tail, _ := newLogTail(&testParent{}, 2)
+		tail.Write([]byte("a\nb\nc\nd\npartial"))
+
+		if err := tail.Close(table.cause); err != nil {
+			t.Fatalf("%s: expected no error, got %v", table.name, err)
+		}
+		if actual := windowOf(tail); !reflect.DeepEqual(actual, table.expected) {
+			t.Errorf("%s: expected window %q, got %q", table.name, table.expected, actual)
+		}
+
+		if _, err := tail.Write([]byte("late\n")); err == nil {
+			t.Errorf("%s: expected writes to a closed tail to fail", table.name)
+		}
+		if err := tail.Close(nil); err == nil {
+			t.Errorf("%s: expected closing twice to fail", table.name)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package component
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+	"github.com/wagoodman/jotframe/pkg/util"
+)
+
+// Units describes how progress values are displayed
+type Units int
+
+const (
+	UnitsCount Units = iota // plain item counts (e.g. "12.3k items/s")
+	UnitsBytes              // byte counts (e.g. "1.5 MiB/s")
+)
+
+const (
+	// rateWindow is the time constant of the moving average used for the rate and ETA
+	rateWindow = 3 * time.Second
+	// defaultWidth is assumed when the terminal width is unknown
+	defaultWidth = 80
+	// minBarWidth is the narrowest bar drawn, anything narrower is omitted
+	minBarWidth = 5
+	bounceWidth = 3
+)
+
+// ProgressBar draws a label, a bar filling the remaining width of the line and throughput statistics. When
+// the total is unknown (zero or less) an indeterminate bouncing animation is drawn instead.
+type ProgressBar struct {
+	line  lineWriter
+	label string
+	units Units
+
+	current int64
+	total   int64
+
+	rate       float64
+	sampled    int64
+	sampledAt  time.Time
+	hasSample  bool
+	bounce     int
+	bounceStep int
+
+	done bool
+	lock sync.Mutex
+	stop func()
+}
+
+// NewProgressBar starts drawing progress toward the given total on a line.
+func NewProgressBar(line *frame.Line, label string, total int64) *ProgressBar {
+	bar := newProgressBar(line, label, total)
+
+	line.WriteString(bar.render(lineWidth()))
+
+	bar.stop = sharedClock.subscribe(bar.onTick)
+	line.OnClose(func(*frame.Line) {
+		bar.Stop()
+	})
+
+	return bar
+}
+
+func newProgressBar(line lineWriter, label string, total int64) *ProgressBar {
+	return &ProgressBar{
+		line:       line,
+		label:      label,
+		total:      total,
+		sampledAt:  time.Now(),
+		bounceStep: 1,
+		stop:       func() {},
+	}
+}
+
+// SetUnits selects how the current value, total and rate are displayed.
+func (bar *ProgressBar) SetUnits(units Units) {
+	bar.lock.Lock()
+	bar.units = units
+	bar.lock.Unlock()
+}
+
+func (bar *ProgressBar) SetLabel(label string) {
+	bar.lock.Lock()
+	bar.label = label
+	bar.lock.Unlock()
+}
+
+// Add increments the current progress value.
+func (bar *ProgressBar) Add(delta int64) {
+	bar.lock.Lock()
+	bar.current += delta
+	bar.lock.Unlock()
+}
+
+// Set replaces the current progress value.
+func (bar *ProgressBar) Set(current int64) {
+	bar.lock.Lock()
+	bar.current = current
+	bar.lock.Unlock()
+}
+
+// SetTotal replaces the total (zero or less means unknown).
+func (bar *ProgressBar) SetTotal(total int64) {
+	bar.lock.Lock()
+	bar.total = total
+	bar.lock.Unlock()
+}
+
+func (bar *ProgressBar) Current() int64 {
+	bar.lock.Lock()
+	defer bar.lock.Unlock()
+	return bar.current
+}
+
+// Done stops the progress bar, draws the final state (the value reached, which is taken as the total when the
+// total is unknown) and closes the line.
+func (bar *ProgressBar) Done() error {
+	contents, err := bar.finish(func() string {
+		// account for the progress made since the last tick in the final rate
+		bar.sample(time.Now())
+		if bar.total <= 0 {
+			bar.total = bar.current
+		}
+		return bar.render(lineWidth())
+	})
+	if err != nil {
+		return err
+	}
+	_, err = bar.line.WriteStringAndClose(contents)
+	return err
+}
+
+// Fail stops the progress bar, draws the label with the given error and closes the line.
+func (bar *ProgressBar) Fail(cause error) error {
+	contents, err := bar.finish(func() string {
+		return fmt.Sprintf("%s %s %v", bar.label, failGlyph, cause)
+	})
+	if err != nil {
+		return err
+	}
+	_, err = bar.line.WriteStringAndClose(contents)
+	return err
+}
+
+// Stop halts any updates without writing to or closing the line.
+func (bar *ProgressBar) Stop() {
+	bar.lock.Lock()
+	bar.done = true
+	bar.lock.Unlock()
+
+	bar.stop()
+}
+
+func (bar *ProgressBar) finish(render func() string) (string, error) {
+	bar.lock.Lock()
+	if bar.done {
+		bar.lock.Unlock()
+		return "", fmt.Errorf("progress bar has already stopped")
+	}
+	bar.done = true
+	contents := render()
+	bar.lock.Unlock()
+
+	bar.stop()
+	return contents, nil
+}
+
+func (bar *ProgressBar) onTick(now time.Time) {
+	bar.lock.Lock()
+	if bar.done {
+		bar.lock.Unlock()
+		return
+	}
+	bar.sample(now)
+	bar.advanceBounce()
+	contents := bar.render(lineWidth())
+	bar.lock.Unlock()
+
+	bar.line.WriteString(contents)
+}
+
+// sample folds the progress made since the previous sample into an exponentially weighted moving average.
+func (bar *ProgressBar) sample(now time.Time) {
+	elapsed := now.Sub(bar.sampledAt)
+	if elapsed <= 0 {
+		return
+	}
+
+	instant := float64(bar.current-bar.sampled) / elapsed.Seconds()
+	if !bar.hasSample {
+		bar.rate = instant
+		bar.hasSample = true
+	} else {
+		weight := 1 - math.Exp(-elapsed.Seconds()/rateWindow.Seconds())
+		bar.rate += weight * (instant - bar.rate)
+	}
+
+	bar.sampled = bar.current
+	bar.sampledAt = now
+}
+
+func (bar *ProgressBar) advanceBounce() {
+	if bar.total > 0 {
+		return
+	}
+	bar.bounce += bar.bounceStep
+}
+
+func (bar *ProgressBar) formatValue(value float64) string {
+	if bar.units == UnitsBytes {
+		return humanizeBytes(value)
+	}
+	return humanizeCount(value)
+}
+
+func (bar *ProgressBar) formatRate() string {
+	if bar.units == UnitsBytes {
+		return humanizeBytes(bar.rate) + "/s"
+	}
+	if bar.rate < 1000 {
+		// slow rates are common for item counts, keep the fractional part
+		return fmt.Sprintf("%.1f items/s", bar.rate)
+	}
+	return humanizeCount(bar.rate) + " items/s"
+}
+
+func (bar *ProgressBar) eta() string {
+	remaining := bar.total - bar.current
+	if remaining <= 0 {
+		return FormatDuration(0)
+	}
+	if bar.rate <= 0 {
+		return "--"
+	}
+	return FormatDuration(time.Duration(float64(remaining) / bar.rate * float64(time.Second)))
+}
+
+func (bar *ProgressBar) stats() string {
+	if bar.total <= 0 {
+		return fmt.Sprintf("%s %s", bar.formatValue(float64(bar.current)), bar.formatRate())
+	}
+
+	percent := int(100 * float64(bar.current) / float64(bar.total))
+	if percent > 100 {
+		percent = 100
+	}
+	return fmt.Sprintf("%3d%% %s/%s %s ETA %s", percent, bar.formatValue(float64(bar.current)), bar.formatValue(float64(bar.total)), bar.formatRate(), bar.eta())
+}
+
+// render draws the label, the bar and the statistics within the given width.
+func (bar *ProgressBar) render(width int) string {
+	stats := bar.stats()
+
+	// the bar (and its two brackets) takes whatever is left after the label, stats and separating spaces
+	label := bar.label
+	if label != "" {
+		label += " "
+	}
+
+	barWidth := width - util.VisualLength(label) - util.VisualLength(stats) - 3
+	if barWidth < minBarWidth {
+		return util.TrimToVisualLength(label+stats, width)
+	}
+
+	return fmt.Sprintf("%s[%s] %s", label, bar.drawBar(barWidth), stats)
+}
+
+func (bar *ProgressBar) drawBar(width int) string {
+	if bar.total <= 0 {
+		return bar.drawBounce(width)
+	}
+
+	filled := int(float64(width) * float64(bar.current) / float64(bar.total))
+	if filled > width {
+		filled = width
+	}
+
+	if filled == width || filled == 0 {
+		return strings.Repeat("=", filled) + strings.Repeat(" ", width-filled)
+	}
+	return strings.Repeat("=", filled-1) + ">" + strings.Repeat(" ", width-filled)
+}
+
+func (bar *ProgressBar) drawBounce(width int) string {
+	span := width - bounceWidth
+	if span <= 0 {
+		return strings.Repeat(" ", width)
+	}
+
+	// bounce between the edges of the bar
+	if bar.bounce >= span {
+		bar.bounce = span
+		bar.bounceStep = -1
+	} else if bar.bounce <= 0 {
+		bar.bounce = 0
+		bar.bounceStep = 1
+	}
+
+	return strings.Repeat(" ", bar.bounce) + "<=>" + strings.Repeat(" ", span-bar.bounce)
+}
+
+// lineWidth is the width available to components on a line.
+func lineWidth() int {
+	width, _ := frame.GetTerminalSize()
+	if width <= 0 {
+		return defaultWidth
+	}
+	return width
+}
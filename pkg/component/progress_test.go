@@ -0,0 +1,116 @@
+package component
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/util"
+)
+
+func Test_ProgressBar_render(t *testing.T) {
+	bar := newProgressBar(nil, "download", 200)
+	bar.current = 50
+	bar.rate = 10
+
+	expected := "download [====>                ]  25% 50/200 10.0 items/s ETA 15s"
+	if actual := bar.render(len(expected)); actual != expected {
+		t.Errorf("ProgressBar.render(): expected\n'%s', got\n'%s'", expected, actual)
+	}
+
+	// the bar fills whatever width remains after the label and statistics
+	for _, width := range []int{60, 80, 120} {
+		if actual := util.VisualLength(bar.render(width)); actual != width {
+			t.Errorf("ProgressBar.render(%d): expected a visual length of %d, got %d", width, width, actual)
+		}
+	}
+
+	// too narrow for a bar
+	if actual := bar.render(20); util.VisualLength(actual) > 20 {
+		t.Errorf("ProgressBar.render(): expected the line to be trimmed, got '%s'", actual)
+	}
+
+	bar.SetLabel("file")
+	bar.SetUnits(UnitsBytes)
+	bar.current = 1024 * 1024
+	bar.total = 4 * 1024 * 1024
+	bar.rate = 512 * 1024
+	expected = "file [===>              ]  25% 1.0 MiB/4.0 MiB 512.0 KiB/s ETA 6s"
+	if actual := bar.render(len([]rune(expected))); actual != expected {
+		t.Errorf("ProgressBar.render(): expected\n'%s', got\n'%s'", expected, actual)
+	}
+}
+
+func Test_ProgressBar_indeterminate(t *testing.T) {
+	bar := newProgressBar(nil, "scan", 0)
+	bar.current = 2000
+
+	first := bar.render(60)
+	bar.advanceBounce()
+	second := bar.render(60)
+
+	if first == second {
+		t.Errorf("ProgressBar.render(): expected the indeterminate animation to move")
+	}
+
+	// the animation bounces back from the far edge
+	for idx := 0; idx < 100; idx++ {
+		bar.advanceBounce()
+		bar.render(60)
+		if bar.bounce < 0 || bar.bounce > 60 {
+			t.Fatalf("ProgressBar.render(): bounce position out of range: %d", bar.bounce)
+		}
+	}
+	if bar.bounceStep == 0 {
+		t.Errorf("ProgressBar.render(): expected the animation to keep moving")
+	}
+}
+
+func Test_ProgressBar_sample(t *testing.T) {
+	start := time.Now()
+	bar := newProgressBar(nil, "copy", 1000)
+	bar.sampledAt = start
+
+	bar.Add(100)
+	bar.sample(start.Add(time.Second))
+	if bar.rate != 100 {
+		t.Errorf("ProgressBar.sample(): expected the first sample to set the rate, got %v", bar.rate)
+	}
+
+	// a burst is smoothed rather than taken as-is
+	bar.Add(1000)
+	bar.sample(start.Add(2 * time.Second))
+	if bar.rate <= 100 || bar.rate >= 1000 {
+		t.Errorf("ProgressBar.sample(): expected a smoothed rate, got %v", bar.rate)
+	}
+
+	weight := 1 - math.Exp(-1/rateWindow.Seconds())
+	expected := 100 + weight*(1000-100)
+	if math.Abs(bar.rate-expected) > 0.0001 {
+		t.Errorf("ProgressBar.sample(): expected rate %v, got %v", expected, bar.rate)
+	}
+}
+
+func Test_ProgressBar_Done(t *testing.T) {
+	line := &testLine{}
+	bar := newProgressBar(line, "copy", 1000)
+	bar.sampledAt = time.Now().Add(-time.Second)
+	bar.Add(400)
+
+	if err := bar.Done(); err != nil {
+		t.Fatalf("ProgressBar.Done(): expected no error, got %v", err)
+	}
+
+	// the bar is drawn at the value reached, not as complete
+	if !line.closed || !strings.Contains(line.last(), " 40% 400/1.0k") {
+		t.Errorf("ProgressBar.Done(): expected the value reached, got '%s'", line.last())
+	}
+	if bar.rate <= 0 || strings.Contains(line.last(), " 0.0 items/s") {
+		t.Errorf("ProgressBar.Done(): expected a final rate, got '%s'", line.last())
+	}
+
+	if err := bar.Done(); err == nil {
+		t.Errorf("ProgressBar.Done(): expected an error for a finished bar")
+	}
+}
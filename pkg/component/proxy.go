@@ -0,0 +1,125 @@
+package component
+
+import (
+	"io"
+	"sync"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+// ProgressReader counts the bytes read through it and reports them on a progress bar. The progress bar is
+// finished when the underlying reader returns io.EOF (or fails with any other error). A reader closed before
+// io.EOF leaves the bar at the number of bytes read.
+type ProgressReader struct {
+	reader   io.Reader
+	bar      *ProgressBar
+	finished sync.Once
+}
+
+// ProgressWriter counts the bytes written through it and reports them on a progress bar. The progress bar is
+// finished on Close (or when the underlying writer fails), showing the number of bytes written against the total.
+type ProgressWriter struct {
+	writer   io.Writer
+	bar      *ProgressBar
+	finished sync.Once
+}
+
+// ProxyReader wraps a reader with a progress display drawn on the given line. A total of zero or less draws an
+// indeterminate progress bar.
+func ProxyReader(line *frame.Line, reader io.Reader, total int64) *ProgressReader {
+	bar := NewProgressBar(line, "", total)
+	bar.SetUnits(UnitsBytes)
+	return &ProgressReader{
+		reader: reader,
+		bar:    bar,
+	}
+}
+
+// ProxyWriter wraps a writer with a progress display drawn on the given line. A total of zero or less draws an
+// indeterminate progress bar.
+func ProxyWriter(line *frame.Line, writer io.Writer, total int64) *ProgressWriter {
+	bar := NewProgressBar(line, "", total)
+	bar.SetUnits(UnitsBytes)
+	return &ProgressWriter{
+		writer: writer,
+		bar:    bar,
+	}
+}
+
+// Bar returns the progress bar (e.g. to set a label).
+func (proxy *ProgressReader) Bar() *ProgressBar {
+	return proxy.bar
+}
+
+func (proxy *ProgressReader) Read(buff []byte) (int, error) {
+	numBytes, err := proxy.reader.Read(buff)
+	proxy.bar.Add(int64(numBytes))
+
+	switch {
+	case err == io.EOF:
+		proxy.finish(nil, true)
+	case err != nil:
+		proxy.finish(err, false)
+	}
+	return numBytes, err
+}
+
+// Close finishes the progress bar (if the reader has not already been exhausted) and closes the underlying
+// reader if it is an io.Closer.
+func (proxy *ProgressReader) Close() error {
+	proxy.finish(nil, false)
+	if closer, ok := proxy.reader.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (proxy *ProgressReader) finish(cause error, exhausted bool) {
+	proxy.finished.Do(func() {
+		finishBar(proxy.bar, cause, exhausted)
+	})
+}
+
+// Bar returns the progress bar (e.g. to set a label).
+func (proxy *ProgressWriter) Bar() *ProgressBar {
+	return proxy.bar
+}
+
+func (proxy *ProgressWriter) Write(buff []byte) (int, error) {
+	numBytes, err := proxy.writer.Write(buff)
+	proxy.bar.Add(int64(numBytes))
+
+	if err != nil {
+		proxy.finish(err)
+	}
+	return numBytes, err
+}
+
+// Close finishes the progress bar and closes the underlying writer if it is an io.Closer.
+func (proxy *ProgressWriter) Close() error {
+	proxy.finish(nil)
+	if closer, ok := proxy.writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (proxy *ProgressWriter) finish(cause error) {
+	proxy.finished.Do(func() {
+		finishBar(proxy.bar, cause, false)
+	})
+}
+
+// finishBar draws the final state of a proxied transfer. Only an exhausted transfer is drawn as complete, taking
+// the bytes transferred as the total (which may have been an estimate).
+func finishBar(bar *ProgressBar, cause error, exhausted bool) {
+	// there is no caller to report drawing errors to (e.g. a line that was removed early)
+	if cause != nil {
+		_ = bar.Fail(cause)
+		return
+	}
+	if exhausted {
+		bar.SetTotal(bar.Current())
+	}
+	_ = bar.Done()
+}
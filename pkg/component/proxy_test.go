@@ -0,0 +1,122 @@
+package component
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func Test_ProxyReader(t *testing.T) {
+	line := &testLine{}
+	source := strings.Repeat("x", 4096)
+	proxy := &ProgressReader{
+		reader: strings.NewReader(source),
+		bar:    newProgressBar(line, "", int64(len(source))),
+	}
+	proxy.bar.SetUnits(UnitsBytes)
+
+	copied, err := ioutil.ReadAll(proxy)
+	if err != nil || len(copied) != len(source) {
+		t.Fatalf("ProgressReader.Read(): expected to read everything (err=%v)", err)
+	}
+
+	if proxy.bar.Current() != int64(len(source)) {
+		t.Errorf("ProgressReader.Read(): expected %d bytes counted, got %d", len(source), proxy.bar.Current())
+	}
+
+	if !line.closed || !strings.Contains(line.last(), "100% 4.0 KiB/4.0 KiB") {
+		t.Errorf("ProgressReader.Read(): expected a completed bar on EOF, got '%s'", line.last())
+	}
+
+	// finishing again is harmless
+	if err := proxy.Close(); err != nil {
+		t.Errorf("ProgressReader.Close(): expected no error, got %v", err)
+	}
+	if len(line.writes) != 1 {
+		t.Errorf("ProgressReader.Close(): expected a single final write, got %d", len(line.writes))
+	}
+}
+
+func Test_ProxyWriter(t *testing.T) {
+	line := &testLine{}
+	var sink bytes.Buffer
+	proxy := &ProgressWriter{
+		writer: &sink,
+		bar:    newProgressBar(line, "upload", 0),
+	}
+
+	io.WriteString(proxy, "hello ")
+	io.WriteString(proxy, "world")
+	if proxy.bar.Current() != 11 || sink.String() != "hello world" {
+		t.Errorf("ProgressWriter.Write(): expected bytes to pass through and be counted")
+	}
+
+	proxy.Close()
+	if !line.closed || !strings.HasPrefix(line.last(), "upload [") {
+		t.Errorf("ProgressWriter.Close(): expected the bar to be finished, got '%s'", line.last())
+	}
+
+	failing := &ProgressWriter{
+		writer: failingWriter{},
+		bar:    newProgressBar(&testLine{}, "upload", 10),
+	}
+	failedLine := failing.bar.line.(*testLine)
+	if _, err := failing.Write([]byte("x")); err == nil {
+		t.Fatalf("ProgressWriter.Write(): expected the underlying error")
+	}
+	if !failedLine.closed || !strings.Contains(failedLine.last(), "✘ disk full") {
+		t.Errorf("ProgressWriter.Write(): expected a failed bar, got '%s'", failedLine.last())
+	}
+}
+
+func Test_Proxy_closedEarly(t *testing.T) {
+	line := &testLine{}
+	writer := &ProgressWriter{
+		writer: ioutil.Discard,
+		bar:    newProgressBar(line, "", 1000),
+	}
+	writer.bar.SetUnits(UnitsBytes)
+
+	writer.Write(make([]byte, 10))
+	writer.Close()
+
+	if !line.closed || !strings.Contains(line.last(), "  1% 10 B/1000 B") {
+		t.Errorf("ProgressWriter.Close(): expected a partial transfer to stay at 1%%, got '%s'", line.last())
+	}
+
+	line = &testLine{}
+	reader := &ProgressReader{
+		reader: strings.NewReader(strings.Repeat("x", 1000)),
+		bar:    newProgressBar(line, "", 1000),
+	}
+	reader.bar.SetUnits(UnitsBytes)
+
+	reader.Read(make([]byte, 250))
+	reader.Close()
+
+	if !line.closed || !strings.Contains(line.last(), " 25% 250 B/1000 B") {
+		t.Errorf("ProgressReader.Close(): expected a partial transfer to stay at 25%%, got '%s'", line.last())
+	}
+
+	// a reader that runs out early is complete at whatever it held
+	line = &testLine{}
+	reader = &ProgressReader{
+		reader: strings.NewReader(strings.Repeat("x", 600)),
+		bar:    newProgressBar(line, "", 1000),
+	}
+	reader.bar.SetUnits(UnitsBytes)
+
+	ioutil.ReadAll(reader)
+	if !line.closed || !strings.Contains(line.last(), "100% 600 B/600 B") {
+		t.Errorf("ProgressReader.Read(): expected a complete bar on EOF, got '%s'", line.last())
+	}
+}
@@ -0,0 +1,171 @@
+package component
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+// SpinnerStyle is the sequence of glyphs a spinner cycles through
+type SpinnerStyle []string
+
+var (
+	SpinnerDots    = SpinnerStyle{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	SpinnerLine    = SpinnerStyle{"-", "\\", "|", "/"}
+	SpinnerBraille = SpinnerStyle{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}
+)
+
+const (
+	successGlyph = "✔"
+	failGlyph    = "✘"
+)
+
+// Spinner animates a glyph followed by a message on a line until it is ended with Success or Fail.
+type Spinner struct {
+	line    lineWriter
+	style   SpinnerStyle
+	message string
+	index   int
+	done    bool
+	lock    sync.Mutex
+	stop    func()
+}
+
+// NewSpinner starts animating the given line. When no style is given SpinnerDots is used. The spinner stops by
+// itself if the line is closed or removed by other means.
+func NewSpinner(line *frame.Line, style SpinnerStyle, message string) *Spinner {
+	if len(style) == 0 {
+		style = SpinnerDots
+	}
+
+	spinner := &Spinner{
+		line:    line,
+		style:   style,
+		message: message,
+	}
+
+	// don't wait for the first tick to show something
+	line.WriteString(spinner.render())
+
+	spinner.stop = sharedClock.subscribe(spinner.onTick)
+	line.OnClose(func(*frame.Line) {
+		spinner.Stop()
+	})
+
+	return spinner
+}
+
+// SetMessage replaces the message shown after the spinner glyph.
+func (spinner *Spinner) SetMessage(message string) {
+	spinner.lock.Lock()
+	spinner.message = message
+	spinner.lock.Unlock()
+}
+
+// Success stops the spinner, writes a success glyph with the given message and closes the line.
+func (spinner *Spinner) Success(message string) error {
+	return spinner.finish(successGlyph, message)
+}
+
+// Fail stops the spinner, writes a failure glyph with the given message and closes the line.
+func (spinner *Spinner) Fail(message string) error {
+	return spinner.finish(failGlyph, message)
+}
+
+// ShowElapsed draws the time elapsed from now as the message of the spinner, refreshed with the animation. The
+// message may contain a "%s" placeholder for the duration (see NewElapsed). The elapsed time stops with the spinner
+// (and is not started at all for a spinner that has already stopped).
+func (spinner *Spinner) ShowElapsed(message string) *Elapsed {
+	start := time.Now()
+	display := newDurationDisplay(spinnerMessage{spinner}, message, func(now time.Time) time.Duration {
+		return now.Sub(start)
+	})
+
+	// a finished spinner would never stop the elapsed time
+	spinner.lock.Lock()
+	done := spinner.done
+	spinner.lock.Unlock()
+	if done {
+		display.Stop()
+		return &Elapsed{display}
+	}
+
+	display.draw(start)
+	display.stop = sharedClock.subscribe(display.draw)
+
+	spinner.lock.Lock()
+	if spinner.done {
+		spinner.lock.Unlock()
+		display.Stop()
+		return &Elapsed{display}
+	}
+	stop := spinner.stop
+	spinner.stop = func() {
+		stop()
+		display.Stop()
+	}
+	spinner.lock.Unlock()
+
+	return &Elapsed{display}
+}
+
+// Stop halts the animation without writing to or closing the line.
+func (spinner *Spinner) Stop() {
+	spinner.lock.Lock()
+	spinner.done = true
+	stop := spinner.stop
+	spinner.lock.Unlock()
+
+	stop()
+}
+
+func (spinner *Spinner) finish(glyph, message string) error {
+	spinner.lock.Lock()
+	if spinner.done {
+		spinner.lock.Unlock()
+		return fmt.Errorf("spinner has already stopped")
+	}
+	spinner.done = true
+	stop := spinner.stop
+	spinner.lock.Unlock()
+
+	stop()
+
+	_, err := spinner.line.WriteStringAndClose(fmt.Sprintf("%s %s", glyph, message))
+	return err
+}
+
+func (spinner *Spinner) onTick(time.Time) {
+	spinner.lock.Lock()
+	if spinner.done {
+		spinner.lock.Unlock()
+		return
+	}
+	spinner.index = (spinner.index + 1) % len(spinner.style)
+	contents := spinner.render()
+	spinner.lock.Unlock()
+
+	// the line may have been closed between the check above and the write (which is harmless)
+	spinner.line.WriteString(contents)
+}
+
+func (spinner *Spinner) render() string {
+	return fmt.Sprintf("%s %s", spinner.style[spinner.index], spinner.message)
+}
+
+// spinnerMessage draws onto the message of a spinner instead of a line
+type spinnerMessage struct {
+	spinner *Spinner
+}
+
+func (message spinnerMessage) WriteString(str string) error {
+	message.spinner.SetMessage(str)
+	return nil
+}
+
+func (message spinnerMessage) WriteStringAndClose(str string) (int, error) {
+	message.spinner.SetMessage(str)
+	return len(str), nil
+}
@@ -0,0 +1,101 @@
+package component
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_Spinner_render(t *testing.T) {
+	spinner := &Spinner{
+		style:   SpinnerLine,
+		message: "building",
+	}
+
+	expected := []string{"- building", "\\ building", "| building", "/ building", "- building"}
+	for idx, value := range expected {
+		if actual := spinner.render(); actual != value {
+			t.Errorf("Spinner.render(): frame %d expected '%s', got '%s'", idx, value, actual)
+		}
+		spinner.index = (spinner.index + 1) % len(spinner.style)
+	}
+
+	spinner.SetMessage("linking")
+	if actual := spinner.render(); actual != "\\ linking" {
+		t.Errorf("Spinner.SetMessage(): expected '\\ linking', got '%s'", actual)
+	}
+}
+
+func Test_Spinner_Stop(t *testing.T) {
+	stopped := false
+	spinner := &Spinner{
+		style: SpinnerDots,
+		stop:  func() { stopped = true },
+	}
+
+	spinner.Stop()
+
+	// a stopped spinner must not advance (or write to its line) on later ticks
+	spinner.onTick(time.Now())
+	if !stopped || spinner.index != 0 {
+		t.Errorf("Spinner.Stop(): expected the spinner to stop animating")
+	}
+
+	if err := spinner.Fail("nope"); err == nil {
+		t.Errorf("Spinner.Fail(): expected an error for a stopped spinner")
+	}
+}
+
+func Test_Spinner_ShowElapsed(t *testing.T) {
+	line := &testLine{}
+	spinner := &Spinner{
+		line:  line,
+		style: SpinnerLine,
+		stop:  func() {},
+	}
+
+	elapsed := spinner.ShowElapsed("make (%s)")
+	if actual := spinner.render(); actual != "- make (0s)" {
+		t.Errorf("Spinner.ShowElapsed(): expected '- make (0s)', got '%s'", actual)
+	}
+
+	elapsed.SetMessage("make (%s): linking")
+	elapsed.draw(time.Now().Add(3 * time.Second))
+	if actual := spinner.render(); actual != "- make (3s): linking" {
+		t.Errorf("Spinner.ShowElapsed(): expected '- make (3s): linking', got '%s'", actual)
+	}
+
+	if err := spinner.Success("done"); err != nil {
+		t.Fatalf("Spinner.Success(): expected no error, got %v", err)
+	}
+	if !elapsed.done {
+		t.Errorf("Spinner.Success(): expected the elapsed time to stop with the spinner")
+	}
+	if line.last() != "✔ done" {
+		t.Errorf("Spinner.Success(): unexpected line contents '%s'", line.last())
+	}
+}
+
+func Test_Spinner_ShowElapsed_stopped(t *testing.T) {
+	line := &testLine{}
+	spinner := &Spinner{
+		line:  line,
+		style: SpinnerLine,
+		stop:  func() {},
+	}
+	spinner.Success("done")
+
+	subscribers := func() int {
+		sharedClock.lock.Lock()
+		defer sharedClock.lock.Unlock()
+		return len(sharedClock.subscribers)
+	}
+	before := subscribers()
+	elapsed := spinner.ShowElapsed("make (%s)")
+
+	if !elapsed.done || subscribers() != before {
+		t.Errorf("Spinner.ShowElapsed(): expected no clock subscription for a finished spinner")
+	}
+	if len(line.writes) != 1 {
+		t.Errorf("Spinner.ShowElapsed(): expected no writes to a finished spinner, got %v", line.writes)
+	}
+}
@@ -0,0 +1,312 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+	"github.com/wagoodman/jotframe/pkg/util"
+)
+
+const (
+	columnSeparator = "  "
+	ellipsis        = "…"
+)
+
+// Column describes a single column of a Table.
+type Column struct {
+	Title string
+	// Flex columns are shrunk (and their cells truncated) first when the table is wider than the terminal
+	Flex bool
+	// MinWidth is the narrowest a flex column is shrunk to before other columns are shrunk
+	MinWidth int
+	// AlignRight right-aligns the cells of the column (e.g. for durations)
+	AlignRight bool
+}
+
+// Table aligns cells into columns across a header line (the column titles) and body lines (one per row). All
+// rows are re-aligned whenever a cell changes the width of a column or the terminal width changes.
+type Table struct {
+	frame   *frame.Frame
+	columns []Column
+	header  lineWriter
+	rows    []*TableRow
+	widths  []int
+	width   int
+	lock    sync.Mutex
+	stop    func()
+}
+
+// TableRow is a single body line of a Table.
+type TableRow struct {
+	table *Table
+	line  *frame.Line
+	cells []string
+}
+
+// NewTable adds a header line with the column titles to the given frame.
+func NewTable(fr *frame.Frame, columns ...Column) (*Table, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("a table requires at least one column")
+	}
+
+	header, err := fr.AppendHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	table := &Table{
+		frame:   fr,
+		columns: columns,
+		header:  header,
+		width:   lineWidth(),
+	}
+
+	table.lock.Lock()
+	table.redraw(true)
+	table.lock.Unlock()
+
+	table.start(header)
+
+	return table, nil
+}
+
+// start watches the terminal width until the header line is closed (e.g. when the frame is closed).
+func (table *Table) start(header *frame.Line) {
+	// there is no resize notification for components, so poll the terminal width on the shared clock
+	table.stop = sharedClock.subscribe(table.onTick)
+
+	header.OnClose(func(*frame.Line) {
+		table.Stop()
+	})
+}
+
+// AddRow appends a body line to the frame for a new row with the given cells.
+func (table *Table) AddRow(cells ...string) (*TableRow, error) {
+	line, err := table.frame.Append()
+	if err != nil {
+		return nil, err
+	}
+
+	table.lock.Lock()
+	defer table.lock.Unlock()
+
+	row := &TableRow{
+		table: table,
+		line:  line,
+		cells: table.normalize(cells),
+	}
+	table.rows = append(table.rows, row)
+	table.redraw(false)
+
+	return row, nil
+}
+
+// RemoveRow removes the line of the given row from the frame (following the trail rules of the frame).
+func (table *Table) RemoveRow(row *TableRow) error {
+	table.lock.Lock()
+	for idx, existing := range table.rows {
+		if existing == row {
+			table.rows = append(table.rows[:idx], table.rows[idx+1:]...)
+			break
+		}
+	}
+	table.redraw(false)
+	table.lock.Unlock()
+
+	return table.frame.Remove(row.line)
+}
+
+// Stop halts watching the terminal width for changes before the header line is closed.
+func (table *Table) Stop() {
+	table.stop()
+}
+
+// Line returns the frame line the row is drawn on.
+func (row *TableRow) Line() *frame.Line {
+	return row.line
+}
+
+// Set replaces the contents of a single cell.
+func (row *TableRow) Set(column int, value string) error {
+	table := row.table
+	table.lock.Lock()
+	defer table.lock.Unlock()
+
+	if column < 0 || column >= len(table.columns) {
+		return fmt.Errorf("invalid column given: %d", column)
+	}
+	row.cells[column] = value
+	return table.redrawRow(row)
+}
+
+// SetCells replaces the contents of all cells of the row.
+func (row *TableRow) SetCells(cells ...string) error {
+	table := row.table
+	table.lock.Lock()
+	defer table.lock.Unlock()
+
+	row.cells = table.normalize(cells)
+	return table.redrawRow(row)
+}
+
+func (table *Table) normalize(cells []string) []string {
+	normalized := make([]string, len(table.columns))
+	copy(normalized, cells)
+	return normalized
+}
+
+func (table *Table) onTick(time.Time) {
+	width := lineWidth()
+
+	table.lock.Lock()
+	defer table.lock.Unlock()
+
+	if width != table.width {
+		table.width = width
+		table.redraw(true)
+	}
+}
+
+// redrawRow draws a single row, or all rows if the change affects the column widths.
+func (table *Table) redrawRow(row *TableRow) error {
+	widths := layoutColumns(table.columns, table.cells(), table.width)
+	if !equalWidths(widths, table.widths) {
+		table.widths = widths
+		return table.drawAll()
+	}
+	return row.line.WriteString(formatRow(table.columns, row.cells, widths))
+}
+
+// redraw re-computes the column widths and draws every row when they have changed (or when forced).
+func (table *Table) redraw(force bool) {
+	widths := layoutColumns(table.columns, table.cells(), table.width)
+	if force || !equalWidths(widths, table.widths) {
+		table.widths = widths
+		table.drawAll()
+		return
+	}
+
+	// the widths are unchanged, however, a newly added row still needs to be drawn
+	if len(table.rows) > 0 {
+		row := table.rows[len(table.rows)-1]
+		row.line.WriteString(formatRow(table.columns, row.cells, widths))
+	}
+}
+
+func (table *Table) drawAll() error {
+	var result error
+	if err := table.header.WriteString(formatRow(table.columns, table.titles(), table.widths)); err != nil {
+		result = err
+	}
+	for _, row := range table.rows {
+		if err := row.line.WriteString(formatRow(table.columns, row.cells, table.widths)); err != nil {
+			result = err
+		}
+	}
+	return result
+}
+
+func (table *Table) titles() []string {
+	titles := make([]string, len(table.columns))
+	for idx, column := range table.columns {
+		titles[idx] = column.Title
+	}
+	return titles
+}
+
+// cells returns the titles and the cells of every row.
+func (table *Table) cells() [][]string {
+	cells := [][]string{table.titles()}
+	for _, row := range table.rows {
+		cells = append(cells, row.cells)
+	}
+	return cells
+}
+
+// layoutColumns computes the width of each column so that the widest cell fits, then shrinks the columns
+// (flex columns first, widest first) until the table fits within the given width.
+func layoutColumns(columns []Column, rows [][]string, width int) []int {
+	widths := make([]int, len(columns))
+	for _, cells := range rows {
+		for idx, cell := range cells {
+			if length := util.VisualLength(cell); length > widths[idx] {
+				widths[idx] = length
+			}
+		}
+	}
+
+	total := util.VisualLength(columnSeparator) * (len(columns) - 1)
+	for _, columnWidth := range widths {
+		total += columnWidth
+	}
+
+	shrink := func(flex bool) {
+		for total > width {
+			widest := -1
+			for idx, column := range columns {
+				minimum := 1
+				if flex {
+					if !column.Flex {
+						continue
+					}
+					if column.MinWidth > minimum {
+						minimum = column.MinWidth
+					}
+				}
+				if widths[idx] > minimum && (widest < 0 || widths[idx] > widths[widest]) {
+					widest = idx
+				}
+			}
+			if widest < 0 {
+				return
+			}
+			widths[widest]--
+			total--
+		}
+	}
+	shrink(true)
+	shrink(false)
+
+	return widths
+}
+
+// formatRow pads (or truncates) each cell to the width of its column.
+func formatRow(columns []Column, cells []string, widths []int) string {
+	formatted := make([]string, len(columns))
+	for idx, column := range columns {
+		cell := fitCell(cells[idx], widths[idx])
+		padding := strings.Repeat(" ", widths[idx]-util.VisualLength(cell))
+		if column.AlignRight {
+			formatted[idx] = padding + cell
+		} else {
+			formatted[idx] = cell + padding
+		}
+	}
+	return strings.TrimRight(strings.Join(formatted, columnSeparator), " ")
+}
+
+// fitCell truncates a cell (marking the truncation with an ellipsis) to the given visual width.
+func fitCell(cell string, width int) string {
+	if util.VisualLength(cell) <= width {
+		return cell
+	}
+	if width <= 1 {
+		return util.TrimToVisualLength(cell, width)
+	}
+	return util.TrimToVisualLength(cell, width-1) + ellipsis
+}
+
+func equalWidths(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for idx := range a {
+		if a[idx] != b[idx] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package component
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wagoodman/jotframe/pkg/util"
+)
+
+var tableTestColumns = []Column{
+	{Title: "NAME", Flex: true, MinWidth: 4},
+	{Title: "STATUS"},
+	{Title: "DURATION", AlignRight: true},
+}
+
+var tableTestRows = [][]string{
+	{"NAME", "STATUS", "DURATION"},
+	{"api-gateway", "running", "1m23s"},
+	{"db", "\x1b[32mhealthy\x1b[0m", "12s"},
+}
+
+func Test_layoutColumns(t *testing.T) {
+	tables := []struct {
+		name     string
+		width    int
+		expected []int
+	}{
+		{"fits", 80, []int{11, 7, 8}},
+		{"exact", 11 + 7 + 8 + 4, []int{11, 7, 8}},
+		{"shrink flex", 25, []int{6, 7, 8}},
+		{"flex at minimum", 22, []int{4, 7, 7}},
+		{"shrink everything", 10, []int{2, 2, 2}},
+	}
+
+	for _, table := range tables {
+		actual := layoutColumns(tableTestColumns, tableTestRows, table.width)
+		if !reflect.DeepEqual(actual, table.expected) {
+			t.Errorf("layoutColumns(): [case=%s] expected %v, got %v", table.name, table.expected, actual)
+		}
+	}
+}
+
+func Test_formatRow(t *testing.T) {
+	widths := layoutColumns(tableTestColumns, tableTestRows, 80)
+
+	expected := []string{
+		"NAME         STATUS   DURATION",
+		"api-gateway  running     1m23s",
+		"db           \x1b[32mhealthy\x1b[0m       12s",
+	}
+	for idx, cells := range tableTestRows {
+		if actual := formatRow(tableTestColumns, cells, widths); actual != expected[idx] {
+			t.Errorf("formatRow(): row %d expected '%s', got '%s'", idx, expected[idx], actual)
+		}
+	}
+
+	// every row shares the same column positions (by visual width)
+	for idx := range tableTestRows {
+		if length := util.VisualLength(formatRow(tableTestColumns, tableTestRows[idx], widths)); length != 30 {
+			t.Errorf("formatRow(): row %d expected a visual length of 30, got %d", idx, length)
+		}
+	}
+
+	narrow := layoutColumns(tableTestColumns, tableTestRows, 25)
+	if actual := formatRow(tableTestColumns, tableTestRows[1], narrow); actual != "api-g…  running     1m23s" {
+		t.Errorf("formatRow(): expected the flex column to be truncated, got '%s'", actual)
+	}
+}
+
+func Test_fitCell(t *testing.T) {
+	tables := []struct {
+		cell     string
+		width    int
+		expected string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"truncated", 6, "trunc…"},
+		{"ab", 1, "a"},
+	}
+
+	for _, table := range tables {
+		if actual := fitCell(table.cell, table.width); actual != table.expected {
+			t.Errorf("fitCell(%s, %d): expected '%s', got '%s'", table.cell, table.width, table.expected, actual)
+		}
+	}
+}
+
+func Test_Table_stopsOnClose(t *testing.T) {
+	subscribers := func() int {
+		sharedClock.lock.Lock()
+		defer sharedClock.lock.Unlock()
+		return len(sharedClock.subscribers)
+	}
+	before := subscribers()
+
+	header := newStandaloneLine()
+	table := &Table{columns: tableTestColumns, header: header}
+	table.start(header)
+
+	if subscribers() != before+1 {
+		t.Fatalf("Table: expected the table to watch the terminal width")
+	}
+
+	// closing the frame closes the header line, which must stop the table without an explicit Stop
+	header.Close()
+	if subscribers() != before {
+		t.Errorf("Table: expected the table to stop once the header line is closed")
+	}
+}
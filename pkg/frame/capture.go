@@ -0,0 +1,114 @@
+//go:build !windows
+// +build !windows
+
+package frame
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// outputCapture redirects the stdout and stderr file descriptors of the process into a pipe so that output from
+// other code (e.g. fmt.Println or the log package) is written to the trail instead of corrupting the frame.
+type outputCapture struct {
+	frame    *Frame
+	stdout   *os.File
+	stderr   *os.File
+	previous *os.File
+	reader   *os.File
+	writer   *os.File
+	done     chan struct{}
+	stopped  sync.Once
+}
+
+func startCapture(frame *Frame) (*outputCapture, error) {
+	stdout, err := dupFile(1, "/dev/stdout")
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := dupFile(2, "/dev/stderr")
+	if err != nil {
+		stdout.Close()
+		return nil, err
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		stdout.Close()
+		stderr.Close()
+		return nil, err
+	}
+
+	for _, fd := range []int{1, 2} {
+		if err := unix.Dup2(int(writer.Fd()), fd); err != nil {
+			unix.Dup2(int(stdout.Fd()), 1)
+			unix.Dup2(int(stderr.Fd()), 2)
+			stdout.Close()
+			stderr.Close()
+			reader.Close()
+			writer.Close()
+			return nil, err
+		}
+	}
+
+	capture := &outputCapture{
+		frame:    frame,
+		stdout:   stdout,
+		stderr:   stderr,
+		previous: getScreen().output,
+		reader:   reader,
+		writer:   writer,
+		done:     make(chan struct{}),
+	}
+
+	// the screen must keep drawing to the terminal, not into the pipe
+	switch capture.previous.Fd() {
+	case 1:
+		getScreen().setWriter(stdout)
+	case 2:
+		getScreen().setWriter(stderr)
+	}
+
+	go capture.forward()
+
+	return capture, nil
+}
+
+func dupFile(fd int, name string) (*os.File, error) {
+	dup, err := unix.Dup(fd)
+	if err != nil {
+		return nil, err
+	}
+	return os.NewFile(uintptr(dup), name), nil
+}
+
+// forward writes every captured line to the trail until the pipe is closed.
+func (capture *outputCapture) forward() {
+	defer close(capture.done)
+
+	writer := capture.frame.TrailWriter()
+	io.Copy(writer, capture.reader)
+	writer.Flush()
+}
+
+// stop restores the original file descriptors and waits for all captured output to reach the trail. This must be
+// called without holding the screen lock.
+func (capture *outputCapture) stop() {
+	capture.stopped.Do(func() {
+		unix.Dup2(int(capture.stdout.Fd()), 1)
+		unix.Dup2(int(capture.stderr.Fd()), 2)
+
+		// with the descriptors restored this is the last writer, so the reader sees EOF once the pipe is drained
+		capture.writer.Close()
+		<-capture.done
+		capture.reader.Close()
+
+		// once the screen is back on the original output nothing writes to the duplicates anymore
+		getScreen().setWriter(capture.previous)
+		capture.stdout.Close()
+		capture.stderr.Close()
+	})
+}
@@ -0,0 +1,80 @@
+//go:build !windows
+// +build !windows
+
+package frame
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func Test_Frame_CaptureOutput(t *testing.T) {
+	getScreen().reset()
+	original := getScreen().output
+
+	frame, err := New(Config{
+		test:           true,
+		startRow:       10,
+		PositionPolicy: PolicyOverflow,
+		ManualDraw:     true,
+		CaptureOutput:  true,
+	})
+	if err != nil {
+		t.Fatalf("Config.CaptureOutput: expected no error, got %v", err)
+	}
+	terminalHeight = 100
+
+	if getScreen().output == original {
+		t.Errorf("Config.CaptureOutput: expected the screen to draw to a duplicate of the original output")
+	}
+
+	fmt.Println("from stdout")
+	fmt.Fprint(os.Stderr, "from stderr\r\npartial")
+
+	frame.Close()
+
+	expected := []string{"from stdout", "from stderr", "partial"}
+	if len(frame.trailRows) != len(expected) {
+		t.Fatalf("Config.CaptureOutput: expected trail %q, got %q", expected, frame.trailRows)
+	}
+	for idx, row := range expected {
+		if frame.trailRows[idx] != row {
+			t.Errorf("Config.CaptureOutput: expected trail %q, got %q", expected, frame.trailRows)
+			break
+		}
+	}
+
+	if getScreen().output != original {
+		t.Errorf("Config.CaptureOutput: expected the screen output to be restored on close")
+	}
+}
+
+func Test_Frame_CaptureOutput_Close(t *testing.T) {
+	descriptors, err := ioutil.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("unable to list open file descriptors")
+	}
+	before := len(descriptors)
+
+	for idx := 0; idx < 50; idx++ {
+		getScreen().reset()
+		frame, err := New(Config{
+			test:           true,
+			startRow:       10,
+			PositionPolicy: PolicyOverflow,
+			ManualDraw:     true,
+			CaptureOutput:  true,
+		})
+		if err != nil {
+			t.Fatalf("Config.CaptureOutput: expected no error, got %v", err)
+		}
+		frame.Close()
+	}
+
+	descriptors, _ = ioutil.ReadDir("/proc/self/fd")
+	if len(descriptors) > before {
+		t.Errorf("Frame.Close(): expected all captured descriptors to be closed (%d open before, %d after)", before, len(descriptors))
+	}
+}
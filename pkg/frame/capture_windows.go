@@ -0,0 +1,13 @@
+package frame
+
+import (
+	"fmt"
+)
+
+type outputCapture struct{}
+
+func startCapture(frame *Frame) (*outputCapture, error) {
+	return nil, fmt.Errorf("output capture is not supported on windows")
+}
+
+func (capture *outputCapture) stop() {}
@@ -0,0 +1,90 @@
+package frame
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CloseMode decides what is left on the screen once the frame is closed.
+type CloseMode int
+
+const (
+	CloseKeep     CloseMode = iota // leave the final contents of the frame on the screen
+	CloseErase                     // erase the frame, giving its rows back to whatever is written next
+	CloseCollapse                  // replace the frame with the single line returned by Config.CloseSummary
+)
+
+func (mode CloseMode) String() string {
+	switch mode {
+	case CloseKeep:
+		return "CloseKeep"
+	case CloseErase:
+		return "CloseErase"
+	case CloseCollapse:
+		return "CloseCollapse"
+	default:
+		return fmt.Sprintf("CloseMode=%d?", mode)
+	}
+}
+
+// SetCloseMode changes what is left on the screen once the frame is closed (overriding Config.CloseMode). This is
+// useful when the outcome is only known at the end, e.g. erasing the frame only when all work has succeeded.
+func (frame *Frame) SetCloseMode(mode CloseMode) {
+	frame.lock.Lock()
+	defer frame.unlock()
+
+	frame.Config.CloseMode = mode
+}
+
+// closeScreen leaves the screen as described by the close mode and moves the cursor past whatever remains.
+func (frame *Frame) closeScreen() {
+	summary, keep := frame.closeSummary()
+	if keep {
+		// move the cursor past the end of the frame
+		getScreen().send(frame.events, ScreenEvent{
+			row:   frame.startIdx + frame.Height(),
+			value: []byte{},
+		})
+		return
+	}
+
+	frame.clear()
+	for _, row := range frame.clearRows {
+		getScreen().send(frame.events, ScreenEvent{
+			row:   row,
+			value: []byte{},
+		})
+	}
+	frame.clearRows = make([]int, 0)
+
+	row := frame.startIdx
+	if summary != "" {
+		getScreen().send(frame.events, ScreenEvent{
+			row:   row,
+			value: []byte(summary),
+		})
+		row++
+	}
+
+	// leave the cursor at the start of the freed rows
+	getScreen().send(frame.events, ScreenEvent{
+		row:   row,
+		value: []byte{},
+	})
+}
+
+// closeSummary returns the line that replaces the frame on close, and whether the frame should be kept as-is
+// instead. A collapsed frame without a summary (or with an empty one) is erased.
+func (frame *Frame) closeSummary() (string, bool) {
+	switch frame.Config.CloseMode {
+	case CloseErase:
+		return "", false
+	case CloseCollapse:
+		if frame.Config.CloseSummary == nil {
+			return "", false
+		}
+		return strings.Split(frame.Config.CloseSummary(frame), lineBreak)[0], false
+	default:
+		return "", true
+	}
+}
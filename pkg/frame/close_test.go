@@ -0,0 +1,125 @@
+package frame
+
+import (
+	"fmt"
+	"testing"
+)
+
+// closeEvents returns all screen events emitted while closing the frame.
+func closeEvents(frame *Frame) []ScreenEvent {
+	for len(frame.events) > 0 {
+		<-frame.events
+	}
+	frame.Close()
+
+	var events []ScreenEvent
+	for len(frame.events) > 0 {
+		events = append(events, <-frame.events)
+	}
+	return events
+}
+
+func Test_Frame_CloseMode(t *testing.T) {
+	tables := []struct {
+		name     string
+		mode     CloseMode
+		summary  func(*Frame) string
+		expected []ScreenEvent
+	}{
+		{
+			name: "keep",
+			mode: CloseKeep,
+			expected: []ScreenEvent{
+				{row: 14, value: []byte("")},
+			},
+		},
+		{
+			name: "erase",
+			mode: CloseErase,
+			expected: []ScreenEvent{
+				{row: 10, value: []byte("")},
+				{row: 11, value: []byte("")},
+				{row: 12, value: []byte("")},
+				{row: 13, value: []byte("")},
+				{row: 10, value: []byte("")},
+			},
+		},
+		{
+			name: "collapse",
+			mode: CloseCollapse,
+			summary: func(frame *Frame) string {
+				return fmt.Sprintf("✔ %d lines done\nignored", len(frame.BodyLines))
+			},
+			expected: []ScreenEvent{
+				{row: 10, value: []byte("")},
+				{row: 11, value: []byte("")},
+				{row: 12, value: []byte("")},
+				{row: 13, value: []byte("")},
+				{row: 10, value: []byte("✔ 3 lines done")},
+				{row: 11, value: []byte("")},
+			},
+		},
+		{
+			name: "collapse without summary",
+			mode: CloseCollapse,
+			expected: []ScreenEvent{
+				{row: 10, value: []byte("")},
+				{row: 11, value: []byte("")},
+				{row: 12, value: []byte("")},
+				{row: 13, value: []byte("")},
+				{row: 10, value: []byte("")},
+			},
+		},
+	}
+
+	for _, table := range tables {
+		frame := newTestFrame(Config{Lines: 3, HeaderRows: 1, CloseMode: table.mode, CloseSummary: table.summary, ManualDraw: true})
+		labelBodyLines(frame, "line %d")
+		frame.Draw()
+		events := closeEvents(frame)
+
+		if len(events) != len(table.expected) {
+			t.Errorf("[case=%s] expected %d events, got %d (%v)", table.name, len(table.expected), len(events), events)
+			continue
+		}
+		for idx, event := range table.expected {
+			if event.row != events[idx].row || string(event.value) != string(events[idx].value) {
+				t.Errorf("[case=%s] event=%d: expected row=%d value='%s', got row=%d value='%s'", table.name, idx, event.row, event.value, events[idx].row, events[idx].value)
+			}
+		}
+	}
+}
+
+func Test_Frame_SetCloseMode(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 3, HeaderRows: 1, ManualDraw: true})
+	labelBodyLines(frame, "line %d")
+	frame.Draw()
+	frame.SetCloseMode(CloseErase)
+
+	events := closeEvents(frame)
+	if len(events) != 5 || events[4].row != 10 {
+		t.Errorf("Frame.SetCloseMode: expected the frame to be erased, got %v", events)
+	}
+
+	// closing again (e.g. when the screen closes) must not touch the rows given back
+	frame.Close()
+	if len(frame.events) != 0 {
+		t.Errorf("Frame.Close(): expected no events when closing a closed frame")
+	}
+}
+
+func Test_screen_Close_concurrentWrites(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 3, HeaderRows: 1, ManualDraw: true})
+	labelBodyLines(frame, "line %d")
+	frame.Draw()
+	defer getScreen().reset()
+
+	// e.g. a forced exit closes the screen while workers are still writing
+	getScreen().Close()
+
+	if err := frame.BodyLines[0].WriteString("late"); err != nil {
+		t.Errorf("Line.WriteString(): expected no error, got %v", err)
+	}
+	frame.AppendTrail("late trail")
+	frame.Draw()
+}
@@ -14,6 +14,39 @@ type Config struct {
 	PositionPolicy PositionPolicy
 	ManualDraw     bool
 	Output         *os.File
+
+	// SortFunc optionally keeps the body lines ordered, reporting whether line a should be drawn before
+	// line b. Nested lines are only ordered among their siblings.
+	SortFunc func(a, b *Line) bool
+
+	// TrailFormatter optionally renders the trail entry for lines removed while TrailOnRemove is set.
+	// Lines may override this with Line.SetTrailFormatter.
+	TrailFormatter TrailFormatter
+
+	// OrderedTrail emits trail entries in the order the body lines were created instead of the order they
+	// were removed. Entries for lines removed early are held until all earlier lines have been removed.
+	OrderedTrail bool
+
+	// MaxBodyHeight optionally caps the number of rows taken by the body, either as an absolute number of rows
+	// (1 or more) or as a fraction of the terminal height (less than 1). Body lines that do not fit are folded
+	// into a single summary row, favoring lines that are still open.
+	MaxBodyHeight float64
+
+	// CaptureOutput redirects the stdout and stderr file descriptors of the process while the frame is open,
+	// writing every captured line to the trail above the frame. The original descriptors are restored on Close.
+	// Note that output written while the process crashes (e.g. a panic) is lost while capturing.
+	CaptureOutput bool
+
+	// CloseMode decides what is left on the screen once the frame is closed: the final contents (the default),
+	// nothing at all, or a single summary line (see CloseSummary).
+	CloseMode CloseMode
+
+	// CloseSummary renders the line that replaces the frame when it is closed with CloseCollapse.
+	CloseSummary func(frame *Frame) string
+
+	// Hooks are lifecycle callbacks invoked for every line in the frame (before any callbacks registered
+	// on the line itself).
+	Hooks Hooks
 }
 
 func (config *Config) VisibleHeight() int {
@@ -0,0 +1,7 @@
+// Package frame draws a block of lines at a fixed place on the terminal while the rest of the output scrolls by.
+//
+// All frames and lines share a single screen lock. Functions given to the frame that only render or order
+// lines (Config.SortFunc, TrailFormatter and Config.CloseSummary) are invoked while that lock is held, so
+// they must not write to lines or otherwise modify the frame. Lifecycle callbacks (see Hooks) run once the
+// lock has been released and have no such restriction.
+package frame
@@ -27,7 +27,16 @@ type Frame struct {
 
 	clearRows       []int
 	trailRows       []string
+	heldTrail       []heldTrailEntry
 	rowAdvancements int
+	lineSeq         int
+
+	keys map[string]*Line
+
+	capture *outputCapture
+
+	// overflowLine summarizes the body lines that do not fit within Config.MaxBodyHeight
+	overflowLine *Line
 
 	events   chan ScreenEvent
 	policy   Policy
@@ -49,6 +58,7 @@ func New(config Config) (*Frame, error) {
 		lock:     scr.lock,
 		autoDraw: !config.ManualDraw,
 		events:   scr.events,
+		keys:     make(map[string]*Line),
 	}
 
 	switch config.PositionPolicy {
@@ -68,15 +78,15 @@ func New(config Config) (*Frame, error) {
 	frame.policy.onInit()
 
 	for idx := 0; idx < config.HeaderRows; idx++ {
-		line := NewLine(frame.startIdx+idx, frame.events)
+		line := frame.newLine(frame.startIdx + idx)
 		frame.HeaderLines = append(frame.HeaderLines, line)
 	}
 	for idx := 0; idx < config.Lines; idx++ {
-		line := NewLine(frame.startIdx+config.HeaderRows+idx, frame.events)
+		line := frame.newLine(frame.startIdx + config.HeaderRows + idx)
 		frame.BodyLines = append(frame.BodyLines, line)
 	}
 	for idx := 0; idx < config.FooterRows; idx++ {
-		line := NewLine(frame.startIdx+config.HeaderRows+config.Lines+idx, frame.events)
+		line := frame.newLine(frame.startIdx + config.HeaderRows + config.Lines + idx)
 		frame.FooterLines = append(frame.FooterLines, line)
 	}
 
@@ -88,6 +98,16 @@ func New(config Config) (*Frame, error) {
 		}
 	}
 
+	// capture only once the frame is known to be valid, otherwise a panic or error would go into the pipe
+	if config.CaptureOutput {
+		capture, err := startCapture(frame)
+		if err != nil {
+			scr.unregister(frame)
+			return nil, err
+		}
+		frame.capture = capture
+	}
+
 	if !config.test {
 		scr.Run()
 	}
@@ -99,6 +119,8 @@ func New(config Config) (*Frame, error) {
 func (frame *Frame) newLine(rowIdx int) *Line {
 	newLine := NewLine(rowIdx, frame.events)
 	newLine.frame = frame
+	newLine.seq = frame.lineSeq
+	frame.lineSeq++
 	return newLine
 }
 
@@ -106,20 +128,32 @@ func (frame *Frame) SetAutoDraw(enabled bool) {
 	frame.autoDraw = enabled
 }
 
+// AppendTrail writes a single row to the trail above the frame (see TrailWriter for arbitrary output).
 func (frame *Frame) AppendTrail(str string) {
 	frame.lock.Lock()
-	defer frame.lock.Unlock()
+	defer frame.unlock()
+
 	frame.appendTrail(str)
+	if frame.autoDraw {
+		frame.draw()
+	}
 }
 
+// appendTrail queues a row for the trail, which is written on the next draw.
 func (frame *Frame) appendTrail(str string) {
 	if !frame.policy.isAllowedTrail() {
 		return
 	}
 	frame.trailRows = append(frame.trailRows, str)
 	frame.policy.onTrail()
+}
 
-	// TODO: what about update/draw here?
+// stopCapture restores the original stdout and stderr (if they were captured), flushing any remaining output to
+// the trail. This must be called without holding the screen lock.
+func (frame *Frame) stopCapture() {
+	if frame.capture != nil {
+		frame.capture.stop()
+	}
 }
 
 func (frame *Frame) visibleBodyLines() int {
@@ -147,7 +181,7 @@ func (frame *Frame) visibleFooterLines() int {
 }
 
 func (frame *Frame) Height() int {
-	return frame.visibleBodyLines() + frame.visibleFooterLines() + frame.visibleHeaderLines()
+	return frame.visibleBodyLines() + frame.overflowHeight() + frame.visibleFooterLines() + frame.visibleHeaderLines()
 }
 
 func (frame *Frame) VisibleHeight() int {
@@ -197,7 +231,7 @@ func (frame *Frame) AppendHeader() (*Line, error) {
 	}
 
 	frame.lock.Lock()
-	defer frame.lock.Unlock()
+	defer frame.unlock()
 
 	var rowIdx int
 	headerLines := frame.visibleHeaderLines()
@@ -233,7 +267,7 @@ func (frame *Frame) AppendFooter() (*Line, error) {
 	}
 
 	frame.lock.Lock()
-	defer frame.lock.Unlock()
+	defer frame.unlock()
 
 	var rowIdx int
 	footerLines := frame.visibleFooterLines()
@@ -263,8 +297,12 @@ func (frame *Frame) Append() (*Line, error) {
 	}
 
 	frame.lock.Lock()
-	defer frame.lock.Unlock()
+	defer frame.unlock()
 
+	return frame.append()
+}
+
+func (frame *Frame) append() (*Line, error) {
 	var rowIdx int
 	bodyLines := frame.visibleBodyLines()
 	if bodyLines > 0 {
@@ -283,7 +321,11 @@ func (frame *Frame) Append() (*Line, error) {
 		footer.move(1)
 	}
 
-	frame.policy.onResize(1)
+	// hidden or nested lines may precede the new line, make certain all rows line up
+	frame.restack()
+
+	// lines beyond Config.MaxBodyHeight take no room, so the cap applies before the policy is told
+	frame.fit(1)
 
 	if frame.autoDraw {
 		frame.draw()
@@ -298,7 +340,7 @@ func (frame *Frame) PrependHeader() (*Line, error) {
 	}
 
 	frame.lock.Lock()
-	defer frame.lock.Unlock()
+	defer frame.unlock()
 
 	rowIdx := frame.startIdx
 
@@ -333,7 +375,7 @@ func (frame *Frame) PrependFooter() (*Line, error) {
 	}
 
 	frame.lock.Lock()
-	defer frame.lock.Unlock()
+	defer frame.unlock()
 
 	rowIdx := frame.startIdx + frame.visibleBodyLines() + frame.visibleHeaderLines()
 
@@ -360,7 +402,7 @@ func (frame *Frame) Prepend() (*Line, error) {
 	}
 
 	frame.lock.Lock()
-	defer frame.lock.Unlock()
+	defer frame.unlock()
 
 	rowIdx := frame.startIdx
 	if frame.HeaderLines != nil {
@@ -378,7 +420,7 @@ func (frame *Frame) Prepend() (*Line, error) {
 		footer.move(1)
 	}
 
-	frame.policy.onResize(1)
+	frame.fit(1)
 
 	if frame.autoDraw {
 		frame.draw()
@@ -389,7 +431,7 @@ func (frame *Frame) Prepend() (*Line, error) {
 
 func (frame *Frame) Insert(index int) (*Line, error) {
 	frame.lock.Lock()
-	defer frame.lock.Unlock()
+	defer frame.unlock()
 	return frame.insert(index, false)
 }
 
@@ -423,7 +465,7 @@ func (frame *Frame) insert(index int, show bool) (*Line, error) {
 		footer.move(1)
 	}
 
-	frame.policy.onResize(1)
+	frame.fit(1)
 
 	if frame.autoDraw {
 		frame.draw()
@@ -515,7 +557,7 @@ func (frame *Frame) moveAfter(moveAdj int, section frameSection, index int) erro
 
 func (frame *Frame) Remove(line *Line) error {
 	frame.lock.Lock()
-	defer frame.lock.Unlock()
+	defer frame.unlock()
 
 	return frame.remove(line, false)
 }
@@ -525,6 +567,31 @@ func (frame *Frame) remove(line *Line, hide bool) error {
 		return fmt.Errorf("frame is closed")
 	}
 
+	if hide {
+		return frame.removeLine(line, true, false)
+	}
+
+	// a removed group collapses into a single trail entry (the parent line), so the descendants are
+	// dropped silently and the frame is drawn once everything has been removed
+	descendants := line.descendants()
+	if len(descendants) > 0 {
+		autoDraw := frame.autoDraw
+		frame.autoDraw = false
+		for idx := len(descendants) - 1; idx >= 0; idx-- {
+			err := frame.removeLine(descendants[idx], false, false)
+			if err != nil {
+				frame.autoDraw = autoDraw
+				return err
+			}
+		}
+		frame.autoDraw = autoDraw
+	}
+	line.detach()
+
+	return frame.removeLine(line, false, frame.Config.TrailOnRemove)
+}
+
+func (frame *Frame) removeLine(line *Line, hide, trail bool) error {
 	// find the index of the line object
 	section, matchedIdx := frame.indexOf(line)
 	if matchedIdx < 0 || section == sectionUnknown {
@@ -532,6 +599,12 @@ func (frame *Frame) remove(line *Line, hide bool) error {
 	}
 	source := frame.section(section)
 
+	if !hide {
+		frame.forget(line)
+		line.cancelExpiry()
+		line.queueHooks(eventRemove)
+	}
+
 	// lines that are removed must be closed since any further writes will result in line clashes
 	if !hide {
 		err := line.close()
@@ -539,7 +612,19 @@ func (frame *Frame) remove(line *Line, hide bool) error {
 			return err
 		}
 	}
-	contents := line.buffer
+
+	// overflowed lines take no space on the screen, so only the overflow summary changes (on the next draw)
+	if line.overflowed {
+		line.overflowed = false
+		if !hide {
+			*source = append((*source)[:matchedIdx], (*source)[matchedIdx+1:]...)
+			frame.trailOverflowed(line, trail)
+		}
+		if frame.autoDraw {
+			frame.draw()
+		}
+		return nil
+	}
 
 	// erase the contents of the last line of the Frame, but persist the line buffer
 	if (line.visible && !hide) || hide {
@@ -561,6 +646,10 @@ func (frame *Frame) remove(line *Line, hide bool) error {
 
 	// no need to adjust any lines if the line was hidden already
 	if !line.visible && !hide {
+		// ...however, this line may have been holding back trail entries from later lines
+		if frame.releaseTrail(false) && frame.autoDraw {
+			frame.draw()
+		}
 		return nil
 	}
 
@@ -570,12 +659,24 @@ func (frame *Frame) remove(line *Line, hide bool) error {
 	}
 
 	// apply policies
-	if !hide && frame.Config.TrailOnRemove {
-		frame.appendTrail(string(contents))
+	var message string
+	if !hide && trail {
+		message, trail = frame.formatTrail(line)
+	}
+
+	if !hide && trail && !frame.Config.OrderedTrail {
+		frame.appendTrailEntry(message)
 	} else {
 		frame.policy.onResize(-1)
 	}
 
+	if !hide {
+		if trail && frame.Config.OrderedTrail {
+			frame.holdTrail(line, message)
+		}
+		frame.releaseTrail(false)
+	}
+
 	if frame.autoDraw {
 		frame.draw()
 	}
@@ -583,9 +684,47 @@ func (frame *Frame) remove(line *Line, hide bool) error {
 	return nil
 }
 
+// restack recomputes the screen row of every line from the frame start row. Hidden lines take no
+// space and share the row of the next visible line.
+func (frame *Frame) restack() {
+	row := frame.startIdx
+	for _, section := range sections {
+		for _, line := range *frame.section(section) {
+			if line.row != row {
+				line.row = row
+				line.stale = true
+			}
+			row += line.height
+		}
+
+		// the overflow summary is drawn after the last body line
+		if section == sectionBody && frame.overflowLine != nil {
+			frame.overflowLine.row = row
+			row += frame.overflowLine.height
+		}
+	}
+}
+
+// reflow re-positions all lines after the frame height has changed by the given number of rows,
+// marking any rows the frame no longer covers to be cleared on the next draw.
+func (frame *Frame) reflow(adjustment int) {
+	if adjustment < 0 {
+		bottom := frame.startIdx + frame.Height()
+		for row := bottom; row < bottom-adjustment; row++ {
+			frame.clearRows = append(frame.clearRows, row)
+		}
+	}
+
+	frame.restack()
+
+	if adjustment != 0 {
+		frame.policy.onResize(adjustment)
+	}
+}
+
 func (frame *Frame) Clear() {
 	frame.lock.Lock()
-	defer frame.lock.Unlock()
+	defer frame.unlock()
 
 	frame.clear()
 
@@ -603,14 +742,20 @@ func (frame *Frame) clear() {
 		frame.clearRows = append(frame.clearRows, line.row)
 	}
 
+	if frame.overflowLine != nil {
+		frame.clearRows = append(frame.clearRows, frame.overflowLine.row)
+	}
+
 	for _, footer := range frame.FooterLines {
 		frame.clearRows = append(frame.clearRows, footer.row)
 	}
 }
 
 func (frame *Frame) Close() error {
+	frame.stopCapture()
+
 	frame.lock.Lock()
-	defer frame.lock.Unlock()
+	defer frame.unlock()
 	err := frame.close()
 	if err != nil {
 		return err
@@ -620,6 +765,16 @@ func (frame *Frame) Close() error {
 }
 
 func (frame *Frame) close() error {
+	// the screen closes all frames, including those that were already closed
+	if frame.closed {
+		return nil
+	}
+
+	// emit any trail entries still waiting on earlier lines (while lines can still be drawn)
+	if frame.releaseTrail(true) {
+		frame.draw()
+	}
+
 	var err error
 	for _, header := range frame.HeaderLines {
 		err = header.close()
@@ -642,13 +797,7 @@ func (frame *Frame) close() error {
 		}
 	}
 
-	// move the cursor past the end of the frame
-	scr := getScreen()
-	event := ScreenEvent{
-		row:   frame.startIdx + frame.Height(),
-		value: []byte{},
-	}
-	scr.events <- event
+	frame.closeScreen()
 
 	frame.closed = true
 	return nil
@@ -685,7 +834,7 @@ func (frame *Frame) Move(rows int) {
 	}
 
 	frame.lock.Lock()
-	defer frame.lock.Unlock()
+	defer frame.unlock()
 
 	frame.move(motion)
 
@@ -707,6 +856,9 @@ func (frame *Frame) move(rows int) {
 	for _, line := range frame.BodyLines {
 		line.move(rows)
 	}
+	if frame.overflowLine != nil {
+		frame.overflowLine.move(rows)
+	}
 	for _, footer := range frame.FooterLines {
 		footer.move(rows)
 	}
@@ -714,7 +866,7 @@ func (frame *Frame) move(rows int) {
 
 func (frame *Frame) Draw() (errs []error) {
 	frame.lock.Lock()
-	defer frame.lock.Unlock()
+	defer frame.unlock()
 	return frame.draw()
 }
 
@@ -722,12 +874,15 @@ func (frame *Frame) draw() (errs []error) {
 	scr := getScreen()
 	errs = make([]error, 0)
 
+	frame.sortBody()
+	frame.fit(0)
+
 	// clear any marked lines (preserving the buffer) while these indexes still exist
 	for _, row := range frame.clearRows {
-		frame.events <- ScreenEvent{
+		getScreen().send(frame.events, ScreenEvent{
 			row:   row,
 			value: []byte{},
-		}
+		})
 	}
 	frame.clearRows = make([]int, 0)
 
@@ -762,7 +917,7 @@ func (frame *Frame) draw() (errs []error) {
 	}
 
 	for _, line := range frame.BodyLines {
-		if line.visible && (line.stale || frame.stale) {
+		if line.shown() && (line.stale || frame.stale) {
 			_, err := line.write(line.buffer)
 			if err != nil {
 				errs = append(errs, err)
@@ -770,6 +925,13 @@ func (frame *Frame) draw() (errs []error) {
 		}
 	}
 
+	if frame.overflowLine != nil {
+		_, err := frame.overflowLine.write(frame.overflowLine.buffer)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	for _, footer := range frame.FooterLines {
 		if footer.visible && (footer.stale || frame.stale) {
 			_, err := footer.write(footer.buffer)
@@ -86,3 +86,24 @@ func validateEvents(t *testing.T, test string, table drawTestParams, errs []erro
 		t.Fatal("Stopping test")
 	}
 }
+
+// newTestFrame creates a frame on a fresh test screen. Only the fields under test need to be given: the frame starts
+// at row 10 of a 100 row terminal unless the config says otherwise.
+func newTestFrame(config Config) *Frame {
+	getScreen().reset()
+	terminalHeight = 100
+
+	config.test = true
+	if config.startRow == 0 {
+		config.startRow = 10
+	}
+	frame, _ := New(config)
+	return frame
+}
+
+// labelBodyLines fills each body line with the given format applied to its index.
+func labelBodyLines(frame *Frame, format string) {
+	for idx, line := range frame.BodyLines {
+		line.buffer = []byte(fmt.Sprintf(format, idx))
+	}
+}
@@ -0,0 +1,125 @@
+package frame
+
+import (
+	"sync"
+)
+
+// Hooks are lifecycle callbacks for lines. Callbacks are invoked after the screen lock has been released,
+// so they may safely write to lines or modify the frame.
+type Hooks struct {
+	// OnWrite is called after the line contents are written (or cleared) by the user
+	OnWrite func(*Line)
+	// OnHide is called after the line is hidden
+	OnHide func(*Line)
+	// OnShow is called after a hidden line is shown again
+	OnShow func(*Line)
+	// OnRemove is called after the line is removed from the frame
+	OnRemove func(*Line)
+	// OnClose is called after the line is closed (this includes lines closed by removal or by closing the frame)
+	OnClose func(*Line)
+}
+
+type lineEvent int
+
+const (
+	eventWrite lineEvent = iota
+	eventHide
+	eventShow
+	eventRemove
+	eventClose
+)
+
+func (hooks Hooks) callback(event lineEvent) func(*Line) {
+	switch event {
+	case eventWrite:
+		return hooks.OnWrite
+	case eventHide:
+		return hooks.OnHide
+	case eventShow:
+		return hooks.OnShow
+	case eventRemove:
+		return hooks.OnRemove
+	case eventClose:
+		return hooks.OnClose
+	default:
+		return nil
+	}
+}
+
+func (line *Line) OnWrite(callback func(*Line)) {
+	line.addHooks(Hooks{OnWrite: callback})
+}
+
+func (line *Line) OnHide(callback func(*Line)) {
+	line.addHooks(Hooks{OnHide: callback})
+}
+
+func (line *Line) OnShow(callback func(*Line)) {
+	line.addHooks(Hooks{OnShow: callback})
+}
+
+func (line *Line) OnRemove(callback func(*Line)) {
+	line.addHooks(Hooks{OnRemove: callback})
+}
+
+func (line *Line) OnClose(callback func(*Line)) {
+	line.addHooks(Hooks{OnClose: callback})
+}
+
+func (line *Line) addHooks(hooks Hooks) {
+	line.lock.Lock()
+	defer line.unlock()
+
+	line.hooks = append(line.hooks, hooks)
+}
+
+// queueHooks schedules the callbacks for the given event (frame-wide callbacks first, then those registered
+// on the line) to run once the screen lock is released. This must be called while holding the screen lock.
+func (line *Line) queueHooks(event lineEvent) {
+	var callbacks []func(*Line)
+	if line.frame != nil {
+		if callback := line.frame.Config.Hooks.callback(event); callback != nil {
+			callbacks = append(callbacks, callback)
+		}
+	}
+	for _, hooks := range line.hooks {
+		if callback := hooks.callback(event); callback != nil {
+			callbacks = append(callbacks, callback)
+		}
+	}
+
+	if len(callbacks) == 0 {
+		return
+	}
+
+	scr := getScreen()
+	scr.callbacks = append(scr.callbacks, func() {
+		for _, callback := range callbacks {
+			callback(line)
+		}
+	})
+}
+
+func (line *Line) unlock() {
+	unlockScreen(line.lock)
+}
+
+func (frame *Frame) unlock() {
+	unlockScreen(frame.lock)
+}
+
+func (scr *screen) unlock() {
+	unlockScreen(scr.lock)
+}
+
+// unlockScreen releases the screen lock and then invokes any lifecycle callbacks queued while it was held.
+func unlockScreen(lock *sync.RWMutex) {
+	scr := getScreen()
+	callbacks := scr.callbacks
+	scr.callbacks = nil
+	lock.Unlock()
+
+	for _, callback := range callbacks {
+		callback()
+	}
+}
@@ -0,0 +1,72 @@
+package frame
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_Line_Hooks(t *testing.T) {
+	var events []string
+	record := func(name string) func(*Line) {
+		return func(line *Line) {
+			events = append(events, name+":"+line.Key())
+		}
+	}
+
+	frame := newTestFrame(Config{
+		Lines: 1,
+		Hooks: Hooks{
+			OnWrite:  record("frame-write"),
+			OnRemove: record("frame-remove"),
+		},
+	})
+
+	line, _ := frame.AppendKeyed("a")
+	line.OnWrite(record("write"))
+	line.OnHide(record("hide"))
+	line.OnShow(record("show"))
+	line.OnRemove(record("remove"))
+	line.OnClose(record("close"))
+
+	line.WriteString("hello")
+	line.Hide()
+	line.Show()
+	line.Close()
+	line.Close()
+	line.Remove()
+
+	expected := []string{
+		"frame-write:a", "write:a",
+		"hide:a",
+		"show:a",
+		"close:a",
+		"frame-remove:a", "remove:a",
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Hooks: expected events %v, got %v", expected, events)
+	}
+}
+
+func Test_Line_Hooks_OutsideLock(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 1})
+
+	var replacement *Line
+	frame.BodyLines[0].OnRemove(func(line *Line) {
+		// this would deadlock if the callback were invoked while holding the screen lock
+		replacement, _ = frame.Append()
+		replacement.WriteString("replacement")
+	})
+
+	err := frame.BodyLines[0].Remove()
+	if err != nil {
+		t.Fatalf("Line.Remove(): expected no error, got %v", err)
+	}
+
+	if replacement == nil || len(frame.BodyLines) != 1 || frame.BodyLines[0] != replacement {
+		t.Errorf("Line.OnRemove(): expected the callback to append a replacement line")
+	}
+
+	if len(getScreen().callbacks) != 0 {
+		t.Errorf("Line.OnRemove(): expected no callbacks to remain queued")
+	}
+}
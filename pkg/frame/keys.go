@@ -0,0 +1,144 @@
+package frame
+
+import (
+	"fmt"
+)
+
+// AppendKeyed adds a new body line to the end of the frame that can later be fetched with Frame.Line.
+func (frame *Frame) AppendKeyed(key string) (*Line, error) {
+	if frame.IsClosed() {
+		return nil, fmt.Errorf("frame is closed")
+	}
+
+	frame.lock.Lock()
+	defer frame.unlock()
+
+	if err := frame.checkKey(key); err != nil {
+		return nil, err
+	}
+
+	line, err := frame.append()
+	if err != nil {
+		return nil, err
+	}
+	frame.setKey(line, key)
+
+	return line, nil
+}
+
+// Line returns the line registered with the given key (or nil if there is no such line).
+func (frame *Frame) Line(key string) *Line {
+	frame.lock.RLock()
+	defer frame.lock.RUnlock()
+
+	return frame.keys[key]
+}
+
+// LinesWithTag returns all lines (headers, body and footers) with the given tag in the order they
+// appear in the frame.
+func (frame *Frame) LinesWithTag(tag string) []*Line {
+	frame.lock.RLock()
+	defer frame.lock.RUnlock()
+
+	var result []*Line
+	for _, section := range sections {
+		for _, line := range *frame.section(section) {
+			if line.HasTag(tag) {
+				result = append(result, line)
+			}
+		}
+	}
+	return result
+}
+
+func (frame *Frame) checkKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("line key must not be empty")
+	}
+	if _, exists := frame.keys[key]; exists {
+		return fmt.Errorf("line key '%s' is already in use", key)
+	}
+	return nil
+}
+
+func (frame *Frame) setKey(line *Line, key string) {
+	if line.key != "" {
+		delete(frame.keys, line.key)
+	}
+	line.key = key
+	frame.keys[key] = line
+}
+
+// forget drops any key registration for a line that is leaving the frame.
+func (frame *Frame) forget(line *Line) {
+	if line.key != "" && frame.keys[line.key] == line {
+		delete(frame.keys, line.key)
+	}
+}
+
+// SetKey registers the line under the given key so it can be fetched with Frame.Line.
+func (line *Line) SetKey(key string) error {
+	line.lock.Lock()
+	defer line.unlock()
+
+	if line.key == key {
+		return nil
+	}
+
+	if line.frame == nil {
+		line.key = key
+		return nil
+	}
+
+	if err := line.frame.checkKey(key); err != nil {
+		return err
+	}
+	line.frame.setKey(line, key)
+	return nil
+}
+
+func (line *Line) Key() string {
+	return line.key
+}
+
+// Tag adds the given tags to the line (tags that are already present are ignored).
+func (line *Line) Tag(tags ...string) {
+	line.lock.Lock()
+	defer line.unlock()
+
+	for _, tag := range tags {
+		if !line.HasTag(tag) {
+			line.tags = append(line.tags, tag)
+		}
+	}
+}
+
+// Untag removes the given tags from the line.
+func (line *Line) Untag(tags ...string) {
+	line.lock.Lock()
+	defer line.unlock()
+
+	for _, tag := range tags {
+		for idx, existing := range line.tags {
+			if existing == tag {
+				line.tags = append(line.tags[:idx], line.tags[idx+1:]...)
+				break
+			}
+		}
+	}
+}
+
+func (line *Line) HasTag(tag string) bool {
+	for _, existing := range line.tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (line *Line) Tags() []string {
+	tags := make([]string, len(line.tags))
+	copy(tags, line.tags)
+	return tags
+}
@@ -0,0 +1,70 @@
+package frame
+
+import (
+	"testing"
+)
+
+func Test_Frame_AppendKeyed(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 2, HeaderRows: 1})
+
+	line, err := frame.AppendKeyed("pkg/foo")
+	if err != nil {
+		t.Fatalf("Frame.AppendKeyed(): expected no error, got %v", err)
+	}
+
+	if line.row != 13 {
+		t.Errorf("Frame.AppendKeyed(): expected line at row 13, got %d", line.row)
+	}
+
+	if frame.Line("pkg/foo") != line || line.Key() != "pkg/foo" {
+		t.Errorf("Frame.Line(): expected to find the keyed line")
+	}
+
+	if _, err := frame.AppendKeyed("pkg/foo"); err == nil {
+		t.Errorf("Frame.AppendKeyed(): expected an error for a duplicate key")
+	}
+
+	if err := frame.BodyLines[0].SetKey("pkg/foo"); err == nil {
+		t.Errorf("Line.SetKey(): expected an error for a duplicate key")
+	}
+
+	frame.BodyLines[0].SetKey("pkg/bar")
+	if frame.Line("pkg/bar") != frame.BodyLines[0] {
+		t.Errorf("Line.SetKey(): expected to find the re-keyed line")
+	}
+
+	frame.Remove(line)
+	if frame.Line("pkg/foo") != nil {
+		t.Errorf("Frame.Remove(): expected the key to be released")
+	}
+
+	if _, err := frame.AppendKeyed("pkg/foo"); err != nil {
+		t.Errorf("Frame.AppendKeyed(): expected a released key to be reusable, got %v", err)
+	}
+}
+
+func Test_Frame_LinesWithTag(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 2, HeaderRows: 1})
+
+	frame.BodyLines[1].Tag("running", "build")
+	frame.HeaderLines[0].Tag("running")
+	frame.BodyLines[0].Tag("build")
+
+	running := frame.LinesWithTag("running")
+	if len(running) != 2 || running[0] != frame.HeaderLines[0] || running[1] != frame.BodyLines[1] {
+		t.Errorf("Frame.LinesWithTag(): unexpected lines %v", running)
+	}
+
+	frame.BodyLines[1].Untag("running")
+	if frame.BodyLines[1].HasTag("running") || len(frame.LinesWithTag("running")) != 1 {
+		t.Errorf("Line.Untag(): expected tag to be removed")
+	}
+
+	if tags := frame.BodyLines[1].Tags(); len(tags) != 1 || tags[0] != "build" {
+		t.Errorf("Line.Tags(): unexpected tags %v", tags)
+	}
+
+	if len(frame.LinesWithTag("missing")) != 0 {
+		t.Errorf("Frame.LinesWithTag(): expected no lines for an unknown tag")
+	}
+}
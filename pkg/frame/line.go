@@ -5,6 +5,7 @@ import (
 	"io"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -20,6 +21,26 @@ type Line struct {
 	closed  bool
 	stale   bool
 	events  chan ScreenEvent
+
+	parent    *Line
+	children  []*Line
+	collapsed bool
+	folded    bool
+
+	overflowed bool
+
+	key  string
+	tags []string
+
+	created        time.Time
+	trailFormatter TrailFormatter
+	seq            int
+
+	expiry    *time.Timer
+	expiryGen int
+	deadline  time.Time
+
+	hooks []Hooks
 }
 
 func NewLine(row int, events chan ScreenEvent) *Line {
@@ -31,6 +52,7 @@ func NewLine(row int, events chan ScreenEvent) *Line {
 		events:  events,
 		height:  1,
 		visible: true,
+		created: time.Now(),
 	}
 }
 
@@ -39,7 +61,7 @@ func (line *Line) notify() error {
 	scr := getScreen()
 
 	event := newScreenEvent(line)
-	line.events <- *event
+	scr.send(line.events, *event)
 	if len(scr.handlers) == 0 {
 		return nil
 	}
@@ -59,7 +81,7 @@ func (line *Line) Row() int {
 
 func (line *Line) Remove() error {
 	line.lock.Lock()
-	defer line.lock.Unlock()
+	defer line.unlock()
 
 	if line.frame != nil {
 		err := line.frame.remove(line, false)
@@ -70,9 +92,10 @@ func (line *Line) Remove() error {
 	return nil
 }
 
+// Hide takes the line off the screen, along with any lines nested under it, while keeping its contents.
 func (line *Line) Hide() error {
 	line.lock.Lock()
-	defer line.lock.Unlock()
+	defer line.unlock()
 
 	line.visible = false
 	line.stale = true
@@ -83,13 +106,22 @@ func (line *Line) Hide() error {
 		if err != nil {
 			return err
 		}
+		// nested lines are folded away along with their parent
+		if len(line.children) > 0 {
+			err = line.frame.refold(line)
+			if err != nil {
+				return err
+			}
+		}
 	}
+	line.queueHooks(eventHide)
 	return nil
 }
 
+// Show puts a hidden line back on the screen, along with the nested lines that were hidden with it.
 func (line *Line) Show() error {
 	line.lock.Lock()
-	defer line.lock.Unlock()
+	defer line.unlock()
 
 	line.visible = true
 	line.stale = true
@@ -101,10 +133,29 @@ func (line *Line) Show() error {
 		if err != nil {
 			return err
 		}
+		if len(line.children) > 0 {
+			err = line.frame.refold(line)
+			if err != nil {
+				return err
+			}
+		}
 	}
+	line.queueHooks(eventShow)
 	return nil
 }
 
+// shown reports whether the line currently occupies a row on the screen.
+func (line *Line) shown() bool {
+	return line.visible && !line.overflowed
+}
+
+// Contents returns the text currently held by the line.
+func (line *Line) Contents() string {
+	line.lock.RLock()
+	defer line.lock.RUnlock()
+	return string(line.buffer)
+}
+
 func (line *Line) IsClosed() bool {
 	return line.closed
 }
@@ -124,9 +175,14 @@ func (line *Line) Clear() error {
 	}
 
 	line.lock.Lock()
-	defer line.lock.Unlock()
+	defer line.unlock()
 
-	return line.clear(false)
+	err := line.clear(false)
+	if err != nil {
+		return err
+	}
+	line.queueHooks(eventWrite)
+	return nil
 }
 
 func (line *Line) clear(preserveBuffer bool) error {
@@ -139,7 +195,7 @@ func (line *Line) clear(preserveBuffer bool) error {
 
 func (line *Line) Read(buff []byte) (int, error) {
 	line.lock.Lock()
-	defer line.lock.Unlock()
+	defer line.unlock()
 	return line.read(buff)
 }
 
@@ -157,9 +213,14 @@ func (line *Line) WriteString(str string) error {
 
 func (line *Line) Write(buff []byte) (int, error) {
 	line.lock.Lock()
-	defer line.lock.Unlock()
+	defer line.unlock()
 
-	return line.write(buff)
+	numBytes, err := line.write(buff)
+	if err != nil {
+		return numBytes, err
+	}
+	line.queueHooks(eventWrite)
+	return numBytes, nil
 }
 
 func (line *Line) write(buff []byte) (int, error) {
@@ -169,6 +230,11 @@ func (line *Line) write(buff []byte) (int, error) {
 
 	line.buffer = []byte(strings.Split(string(buff), lineBreak)[0])
 
+	// lines folded away by Config.MaxBodyHeight keep their contents for when there is room for them again
+	if line.overflowed {
+		return len(line.buffer), nil
+	}
+
 	// only enforce terminal bounds checking when we positively know the terminal size
 	if terminalHeight > -1 {
 		if line.row < 0 || line.row > terminalHeight {
@@ -180,40 +246,37 @@ func (line *Line) write(buff []byte) (int, error) {
 }
 
 func (line *Line) WriteStringAndClose(str string) (int, error) {
-	// WriteString already uses Draw() which will implicitly lock
-	numBytes, err := io.WriteString(line, str)
-	if err != nil {
-		return -1, err
-	}
-	return numBytes, line.close()
+	return line.WriteAndClose([]byte(str))
 }
 
 func (line *Line) WriteAndClose(buff []byte) (int, error) {
 	line.lock.Lock()
-	defer line.lock.Unlock()
+	defer line.unlock()
 
 	numBytes, err := line.write(buff)
 	if err != nil {
 		return -1, err
 	}
+	line.queueHooks(eventWrite)
 
 	return numBytes, line.close()
 }
 
 func (line *Line) ClearAndClose() error {
 	line.lock.Lock()
-	defer line.lock.Unlock()
+	defer line.unlock()
 
 	err := line.clear(false)
 	if err != nil {
 		return err
 	}
+	line.queueHooks(eventWrite)
 	return line.close()
 }
 
 func (line *Line) Open() error {
 	line.lock.Lock()
-	defer line.lock.Unlock()
+	defer line.unlock()
 
 	return line.open()
 }
@@ -226,12 +289,15 @@ func (line *Line) open() error {
 
 func (line *Line) Close() error {
 	line.lock.Lock()
-	defer line.lock.Unlock()
+	defer line.unlock()
 
 	return line.close()
 }
 
 func (line *Line) close() error {
+	if !line.closed {
+		line.queueHooks(eventClose)
+	}
 	line.closed = true
 
 	return nil
@@ -0,0 +1,180 @@
+package frame
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MoveLine moves a body line (along with any nested lines) to the given index among its siblings. For
+// top-level lines in a frame without nested groups this is the same as the index within BodyLines.
+func (frame *Frame) MoveLine(line *Line, index int) error {
+	frame.lock.Lock()
+	defer frame.unlock()
+
+	return frame.moveLine(line, index)
+}
+
+func (frame *Frame) moveLine(line *Line, index int) error {
+	if frame.IsClosed() {
+		return fmt.Errorf("frame is closed")
+	}
+
+	if frame.Config.SortFunc != nil {
+		return fmt.Errorf("body lines are kept sorted by Config.SortFunc")
+	}
+
+	siblings, matchedIdx := frame.siblings(line)
+	if matchedIdx < 0 {
+		return fmt.Errorf("could not find line in frame")
+	}
+
+	if index < 0 || index >= len(siblings) {
+		return fmt.Errorf("invalid index given")
+	}
+
+	siblings = append(siblings[:matchedIdx], siblings[matchedIdx+1:]...)
+	siblings = append(siblings, nil)
+	copy(siblings[index+1:], siblings[index:])
+	siblings[index] = line
+
+	frame.reorder(line.parent, siblings)
+
+	return nil
+}
+
+// MoveTo moves the line to the given index among its siblings (see Frame.MoveLine).
+func (line *Line) MoveTo(index int) error {
+	line.lock.Lock()
+	defer line.unlock()
+
+	if line.frame == nil {
+		return fmt.Errorf("line is not attached to a frame")
+	}
+	return line.frame.moveLine(line, index)
+}
+
+// Swap exchanges the positions of two body lines (along with any nested lines). Both lines must
+// share the same parent.
+func (frame *Frame) Swap(a, b *Line) error {
+	frame.lock.Lock()
+	defer frame.unlock()
+
+	return frame.swap(a, b)
+}
+
+func (frame *Frame) swap(a, b *Line) error {
+	if frame.IsClosed() {
+		return fmt.Errorf("frame is closed")
+	}
+
+	if frame.Config.SortFunc != nil {
+		return fmt.Errorf("body lines are kept sorted by Config.SortFunc")
+	}
+
+	if a.parent != b.parent {
+		return fmt.Errorf("only lines with the same parent may be swapped")
+	}
+
+	siblings, aIdx := frame.siblings(a)
+	_, bIdx := frame.siblings(b)
+	if aIdx < 0 || bIdx < 0 {
+		return fmt.Errorf("could not find line in frame")
+	}
+
+	siblings[aIdx], siblings[bIdx] = siblings[bIdx], siblings[aIdx]
+
+	frame.reorder(a.parent, siblings)
+
+	return nil
+}
+
+// Sort re-orders the body lines with Config.SortFunc. This happens implicitly on every draw, however,
+// it can be useful when the sort key of a line changes without any structural change to the frame.
+func (frame *Frame) Sort() {
+	frame.lock.Lock()
+	defer frame.unlock()
+
+	frame.sortBody()
+
+	if frame.autoDraw {
+		frame.draw()
+	}
+}
+
+func (frame *Frame) sortBody() {
+	less := frame.Config.SortFunc
+	if less == nil {
+		return
+	}
+
+	var sortLines func(lines []*Line)
+	sortLines = func(lines []*Line) {
+		sort.SliceStable(lines, func(i, j int) bool {
+			return less(lines[i], lines[j])
+		})
+		for _, line := range lines {
+			sortLines(line.children)
+		}
+	}
+
+	roots := frame.rootLines()
+	sortLines(roots)
+	frame.setBody(roots)
+}
+
+// siblings returns a copy of the lines that share the same parent as the given line (including the
+// line itself) along with the index of the given line within that list.
+func (frame *Frame) siblings(line *Line) ([]*Line, int) {
+	var siblings []*Line
+	if line.parent != nil {
+		siblings = make([]*Line, len(line.parent.children))
+		copy(siblings, line.parent.children)
+	} else {
+		siblings = frame.rootLines()
+	}
+
+	for idx, sibling := range siblings {
+		if sibling == line {
+			return siblings, idx
+		}
+	}
+	return siblings, -1
+}
+
+// rootLines returns all body lines that are not nested under another line.
+func (frame *Frame) rootLines() []*Line {
+	var roots []*Line
+	for _, line := range frame.BodyLines {
+		if line.parent == nil {
+			roots = append(roots, line)
+		}
+	}
+	return roots
+}
+
+// reorder replaces the children of the given parent (or the top-level lines when there is no parent)
+// with the given ordering and repaints the frame.
+func (frame *Frame) reorder(parent *Line, siblings []*Line) {
+	if parent != nil {
+		parent.children = siblings
+		frame.setBody(frame.rootLines())
+	} else {
+		frame.setBody(siblings)
+	}
+
+	if frame.autoDraw {
+		frame.draw()
+	}
+}
+
+// setBody rebuilds the body lines from the given top-level lines (each followed by its descendants)
+// and recomputes the screen row of every line.
+func (frame *Frame) setBody(roots []*Line) {
+	body := make([]*Line, 0, len(frame.BodyLines))
+	for _, root := range roots {
+		body = append(body, root)
+		body = append(body, root.descendants()...)
+	}
+	frame.BodyLines = body
+	frame.restack()
+}
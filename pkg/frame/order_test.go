@@ -0,0 +1,104 @@
+package frame
+
+import (
+	"testing"
+)
+
+func bodyContents(frame *Frame) string {
+	var result string
+	for _, line := range frame.BodyLines {
+		result += string(line.buffer)
+	}
+	return result
+}
+
+func validateBodyRows(t *testing.T, frame *Frame) {
+	for idx, line := range frame.BodyLines {
+		if expectedRow := frame.startIdx + 1 + idx; line.row != expectedRow {
+			t.Errorf("expected body line %d at row %d, got %d", idx, expectedRow, line.row)
+		}
+	}
+}
+
+func Test_Frame_MoveLine(t *testing.T) {
+	tables := []struct {
+		from     int
+		to       int
+		expected string
+	}{
+		{0, 3, "12304"},
+		{4, 0, "40123"},
+		{2, 2, "01234"},
+		{1, 3, "02314"},
+	}
+
+	for _, table := range tables {
+		frame := newTestFrame(Config{Lines: 5, HeaderRows: 1, FooterRows: 1})
+		labelBodyLines(frame, "%d")
+
+		err := frame.MoveLine(frame.BodyLines[table.from], table.to)
+		if err != nil {
+			t.Fatalf("Frame.MoveLine(): expected no error, got %v", err)
+		}
+
+		if actual := bodyContents(frame); actual != table.expected {
+			t.Errorf("Frame.MoveLine(%d, %d): expected '%s', got '%s'", table.from, table.to, table.expected, actual)
+		}
+		validateBodyRows(t, frame)
+	}
+
+	frame := newTestFrame(Config{Lines: 2, HeaderRows: 1, FooterRows: 1})
+	labelBodyLines(frame, "%d")
+	if err := frame.BodyLines[0].MoveTo(2); err == nil {
+		t.Errorf("Line.MoveTo(): expected an error for an invalid index")
+	}
+}
+
+func Test_Frame_Swap(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 4, HeaderRows: 1, FooterRows: 1})
+	labelBodyLines(frame, "%d")
+	first := frame.BodyLines[0]
+	child, _ := first.AppendChild()
+	child.buffer = []byte("c")
+
+	err := frame.Swap(first, frame.BodyLines[4])
+	if err != nil {
+		t.Fatalf("Frame.Swap(): expected no error, got %v", err)
+	}
+
+	// the nested line travels along with its parent
+	if actual := bodyContents(frame); actual != "3120c" {
+		t.Errorf("Frame.Swap(): expected '3120c', got '%s'", actual)
+	}
+	validateBodyRows(t, frame)
+
+	if err := frame.Swap(child, frame.BodyLines[0]); err == nil {
+		t.Errorf("Frame.Swap(): expected an error when swapping lines with different parents")
+	}
+}
+
+func Test_Frame_SortFunc(t *testing.T) {
+	// open lines are drawn before closed lines
+	frame := newTestFrame(Config{
+		Lines:      5,
+		HeaderRows: 1,
+		FooterRows: 1,
+		SortFunc: func(a, b *Line) bool {
+			return !a.IsClosed() && b.IsClosed()
+		},
+	})
+	labelBodyLines(frame, "%d")
+
+	frame.BodyLines[0].Close()
+	frame.BodyLines[2].Close()
+	frame.Draw()
+
+	if actual := bodyContents(frame); actual != "13402" {
+		t.Errorf("Config.SortFunc: expected '13402', got '%s'", actual)
+	}
+	validateBodyRows(t, frame)
+
+	if err := frame.MoveLine(frame.BodyLines[0], 1); err == nil {
+		t.Errorf("Frame.MoveLine(): expected an error for a sorted frame")
+	}
+}
@@ -0,0 +1,126 @@
+package frame
+
+import (
+	"fmt"
+)
+
+// overflowSummary is drawn in place of the body lines that do not fit within Config.MaxBodyHeight
+const overflowSummary = "…and %d more (%d running)"
+
+// maxBodyHeight returns the number of rows the body may take (or 0 when there is no limit).
+func (frame *Frame) maxBodyHeight() int {
+	limit := frame.Config.MaxBodyHeight
+	switch {
+	case limit <= 0:
+		return 0
+	case limit < 1:
+		// a fraction of an unknown terminal height can't be honored
+		if terminalHeight <= 0 {
+			return 0
+		}
+		rows := int(limit * float64(terminalHeight))
+		if rows < 1 {
+			rows = 1
+		}
+		return rows
+	default:
+		return int(limit)
+	}
+}
+
+// fit enforces Config.MaxBodyHeight: when there are more body lines than rows allowed, the most relevant lines
+// (open lines first, then closed lines, each in body order) are kept on the screen and the rest are folded into
+// a single summary row. Folded lines keep accepting writes and are drawn again once there is room.
+//
+// The adjustment is the change in height (e.g. from adding a line) that the position policy has not been told about
+// yet, so that the policy only ever sees the height of the frame after the cap has been applied.
+func (frame *Frame) fit(adjustment int) {
+	limit := frame.maxBodyHeight()
+	if limit == 0 && frame.overflowLine == nil {
+		frame.reflow(adjustment)
+		return
+	}
+
+	before := frame.Height() - adjustment
+
+	var candidates []*Line
+	for _, line := range frame.BodyLines {
+		if line.visible {
+			candidates = append(candidates, line)
+		}
+	}
+
+	keep := make(map[*Line]bool)
+	if limit == 0 || len(candidates) <= limit {
+		for _, line := range candidates {
+			keep[line] = true
+		}
+	} else {
+		// one row goes to the summary
+		slots := limit - 1
+		for _, closed := range []bool{false, true} {
+			for _, line := range candidates {
+				if slots > 0 && line.closed == closed {
+					keep[line] = true
+					slots--
+				}
+			}
+		}
+	}
+
+	overflowed, running := 0, 0
+	for _, line := range candidates {
+		if keep[line] {
+			if line.overflowed {
+				line.overflowed = false
+				line.height = 1
+				line.stale = true
+			}
+			continue
+		}
+		line.overflowed = true
+		line.height = 0
+		overflowed++
+		if !line.closed {
+			running++
+		}
+	}
+
+	// the summary row moves (or goes away), so make sure the row it was drawn on does not keep stale contents
+	if frame.overflowLine != nil {
+		frame.clearRows = append(frame.clearRows, frame.overflowLine.row)
+	}
+
+	if overflowed == 0 {
+		frame.overflowLine = nil
+	} else {
+		if frame.overflowLine == nil {
+			frame.overflowLine = NewLine(0, frame.events)
+		}
+		frame.overflowLine.buffer = []byte(fmt.Sprintf(overflowSummary, overflowed, running))
+	}
+
+	frame.reflow(frame.Height() - before)
+}
+
+func (frame *Frame) overflowHeight() int {
+	if frame.overflowLine == nil {
+		return 0
+	}
+	return frame.overflowLine.height
+}
+
+// trailOverflowed writes the trail entry of a removed line that was not on the screen. Unlike a visible line, the
+// trail entry does not take over the row of the removed line.
+func (frame *Frame) trailOverflowed(line *Line, trail bool) {
+	if trail {
+		message, keep := frame.formatTrail(line)
+		switch {
+		case keep && frame.Config.OrderedTrail:
+			frame.holdTrail(line, message)
+		case keep:
+			frame.appendTrailEntry(message)
+		}
+	}
+	frame.releaseTrail(false)
+}
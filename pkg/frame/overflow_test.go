@@ -0,0 +1,135 @@
+package frame
+
+import (
+	"testing"
+)
+
+func Test_Frame_MaxBodyHeight(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 5, FooterRows: 1, MaxBodyHeight: 3, TrailOnRemove: true, ManualDraw: true})
+	frame.Draw()
+
+	if frame.Height() != 4 {
+		t.Fatalf("Config.MaxBodyHeight: expected a height of 4, got %d", frame.Height())
+	}
+
+	for idx, line := range frame.BodyLines {
+		if line.overflowed != (idx >= 2) {
+			t.Errorf("Config.MaxBodyHeight: unexpected overflow state for line %d", idx)
+		}
+	}
+
+	if actual := string(frame.overflowLine.buffer); actual != "…and 3 more (3 running)" {
+		t.Errorf("Config.MaxBodyHeight: unexpected summary '%s'", actual)
+	}
+
+	if frame.BodyLines[1].row != 11 || frame.overflowLine.row != 12 || frame.FooterLines[0].row != 13 {
+		t.Errorf("Config.MaxBodyHeight: unexpected rows (line=%d summary=%d footer=%d)", frame.BodyLines[1].row, frame.overflowLine.row, frame.FooterLines[0].row)
+	}
+
+	// open lines are favored over closed lines
+	frame.BodyLines[0].Close()
+	frame.Draw()
+
+	if frame.BodyLines[0].shown() || !frame.BodyLines[2].shown() {
+		t.Errorf("Config.MaxBodyHeight: expected a closed line to give way to an open line")
+	}
+	if actual := string(frame.overflowLine.buffer); actual != "…and 3 more (2 running)" {
+		t.Errorf("Config.MaxBodyHeight: unexpected summary '%s'", actual)
+	}
+
+	// overflowed lines keep accepting writes without drawing
+	pending := len(frame.events)
+	frame.BodyLines[4].WriteString("still working")
+	if len(frame.events) != pending || string(frame.BodyLines[4].buffer) != "still working" {
+		t.Errorf("Line.Write(): expected an overflowed line to buffer writes without drawing")
+	}
+}
+
+func Test_Frame_MaxBodyHeight_Remove(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 4, FooterRows: 1, MaxBodyHeight: 3, TrailOnRemove: true, ManualDraw: true})
+	frame.Draw()
+
+	overflowed := frame.BodyLines[3]
+	overflowed.WriteString("finished")
+	frame.Remove(overflowed)
+
+	// the removed line was not on the screen, so its trail entry only pushes the frame down
+	if len(frame.trailRows) != 1 || frame.trailRows[0] != "finished" {
+		t.Errorf("Frame.Remove(): expected a trail entry for an overflowed line (trail=%v)", frame.trailRows)
+	}
+	frame.Draw()
+
+	if frame.overflowLine != nil || frame.Height() != 4 {
+		t.Errorf("Frame.Remove(): expected the summary to go away once everything fits (height=%d)", frame.Height())
+	}
+
+	for _, line := range frame.BodyLines {
+		if !line.shown() {
+			t.Errorf("Frame.Remove(): expected all remaining lines to be shown")
+		}
+	}
+}
+
+func Test_Frame_MaxBodyHeight_RemoveMultiline(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 4, MaxBodyHeight: 3, TrailOnRemove: true, ManualDraw: true})
+	frame.Draw()
+
+	overflowed := frame.BodyLines[3]
+	overflowed.WriteString("failed")
+	overflowed.SetTrailFormatter(func(entry TrailEntry) (string, bool) {
+		return entry.Content + "\ndetail", true
+	})
+	frame.Remove(overflowed)
+
+	if len(frame.trailRows) != 2 || frame.trailRows[0] != "failed" || frame.trailRows[1] != "detail" {
+		t.Errorf("Frame.Remove(): expected a trail row per line of an overflowed entry (trail=%v)", frame.trailRows)
+	}
+	if frame.startIdx != 12 {
+		t.Errorf("Frame.Remove(): expected the frame to move down a row per trail row, got %d", frame.startIdx)
+	}
+}
+
+func Test_Frame_maxBodyHeight(t *testing.T) {
+	tables := []struct {
+		limit    float64
+		height   int
+		expected int
+	}{
+		{0, 100, 0},
+		{12, 100, 12},
+		{0.25, 100, 25},
+		{0.001, 100, 1},
+		{0.5, -1, 0},
+	}
+
+	for _, table := range tables {
+		frame := newTestFrame(Config{MaxBodyHeight: table.limit})
+		terminalHeight = table.height
+		if actual := frame.maxBodyHeight(); actual != table.expected {
+			t.Errorf("Frame.maxBodyHeight(%v): expected %d, got %d", table.limit, table.expected, actual)
+		}
+	}
+	terminalHeight = 100
+}
+
+func Test_Frame_MaxBodyHeight_FloatForward(t *testing.T) {
+	frame := newTestFrame(Config{
+		Lines:          3,
+		startRow:       97,
+		MaxBodyHeight:  3,
+		PositionPolicy: PolicyFloatForward,
+		ManualDraw:     true,
+	})
+
+	// the new line is folded into the summary, so the frame does not grow and there is no need to make room
+	if _, err := frame.Append(); err != nil {
+		t.Fatalf("Frame.Append(): expected no error, got %v", err)
+	}
+
+	if frame.startIdx != 97 || frame.rowAdvancements != 0 {
+		t.Errorf("Frame.Append(): expected the frame to stay at row 97, got %d (advancements=%d)", frame.startIdx, frame.rowAdvancements)
+	}
+	if frame.Height() != 3 {
+		t.Errorf("Frame.Append(): expected a capped height of 3, got %d", frame.Height())
+	}
+}
@@ -15,12 +15,14 @@ var (
 type screen struct {
 	lock      *sync.RWMutex
 	closeLock *sync.RWMutex
+	writeLock *sync.Mutex
 	events    chan ScreenEvent
 	frames    []*Frame
 	handlers  []EventHandler
 	closed    bool
 	workers   *sync.WaitGroup
 	output    *os.File
+	callbacks []func()
 }
 
 func getScreen() *screen {
@@ -28,6 +30,7 @@ func getScreen() *screen {
 		theScr = &screen{
 			lock:      &sync.RWMutex{},
 			closeLock: &sync.RWMutex{},
+			writeLock: &sync.Mutex{},
 			output:    os.Stdout,
 		}
 		theScr.reset()
@@ -35,8 +38,11 @@ func getScreen() *screen {
 	return theScr
 }
 
+// setWriter changes the output of the screen. Once this returns no event is being written to the previous output.
 func (scr *screen) setWriter(writer *os.File) {
+	scr.writeLock.Lock()
 	scr.output = writer
+	scr.writeLock.Unlock()
 	// now there is a different fd which to ask for screen dimensions from
 	updateScreenDimensions()
 }
@@ -46,6 +52,7 @@ func (scr *screen) reset() {
 	defer scr.lock.Unlock()
 
 	theScr.events = make(chan ScreenEvent, 100000)
+	theScr.closed = false
 	theScr.frames = make([]*Frame, 0)
 	theScr.handlers = make([]EventHandler, 0)
 	theScr.workers = &sync.WaitGroup{}
@@ -59,6 +66,15 @@ func (scr *screen) register(frame *Frame) error {
 	return nil
 }
 
+func (scr *screen) unregister(frame *Frame) {
+	for idx, registered := range scr.frames {
+		if registered == frame {
+			scr.frames = append(scr.frames[:idx], scr.frames[idx+1:]...)
+			return
+		}
+	}
+}
+
 func (scr *screen) addScreenHandler(handler EventHandler) {
 	scr.handlers = append(scr.handlers, handler)
 }
@@ -78,29 +94,54 @@ func Close() error {
 }
 
 func (scr *screen) Close() error {
-	scr.closeLock.Lock()
-	defer scr.closeLock.Unlock()
+	// captured output is written to the trail, which needs the screen lock
+	scr.lock.RLock()
+	frames := make([]*Frame, len(scr.frames))
+	copy(frames, scr.frames)
+	scr.lock.RUnlock()
+	for _, frame := range frames {
+		frame.stopCapture()
+	}
 
+	scr.lock.Lock()
+	if scr.closed {
+		scr.unlock()
+		return nil
+	}
 	for _, frame := range scr.frames {
 		frame.close()
 	}
+	scr.unlock()
 	// allow the frames to exist as a trail now. advance the screen to allow room for the cursor.
 	row, _ := GetCursorRow()
+
+	// lines may still be written from other goroutines, which must find the screen closed (see send)
+	scr.lock.Lock()
+	scr.closeLock.Lock()
 	if row == terminalHeight {
 		scr.events <- ScreenEvent{
 			row:   terminalHeight,
 			value: []byte(lineBreak),
 		}
 	}
-
 	scr.closed = true
 	close(scr.events)
+	scr.closeLock.Unlock()
+	scr.unlock()
 
 	scr.workers.Wait()
 
 	return nil
 }
 
+// send queues an event to be drawn unless the screen has been closed. This must be called with the screen lock held.
+func (scr *screen) send(events chan ScreenEvent, event ScreenEvent) {
+	if scr.closed {
+		return
+	}
+	events <- event
+}
+
 func (scr *screen) advance(rows int) {
 	scr.closeLock.RLock()
 	defer scr.closeLock.RUnlock()
@@ -133,35 +174,46 @@ func (scr *screen) Run() {
 		defer scr.workers.Done()
 
 		for event := range scr.events {
-			// clear the row
-			err := setCursorRow(event.row)
-			if err != nil {
-				fmt.Printf("failed to set cursor row: %s\n", err)
-				scr.closed = true
-				return
-			}
-			// erase line (set mode=2)
-			_, err = fmt.Fprintf(scr.output, "\x1b[%dK", 2)
-			if err != nil {
-				fmt.Printf("failed to erase line: %s\n", err)
-				scr.closed = true
-				return
-			}
-			// set cursor horizontal absolute position to 0
-			_, err = fmt.Fprintf(scr.output, "\x1b[%dG", 0)
-			if err != nil {
-				fmt.Printf("failed to set horizontal position: %s\n", err)
-				scr.closed = true
-				return
-			}
-
-			// write the output
-			_, err = scr.output.Write(event.value)
-			if err != nil {
-				fmt.Printf("failed to write payload: %s\n", err)
-				scr.closed = true
+			if !scr.write(event) {
 				return
 			}
 		}
 	}()
 }
+
+// write draws a single event, reporting whether the screen is still usable.
+func (scr *screen) write(event ScreenEvent) bool {
+	scr.writeLock.Lock()
+	defer scr.writeLock.Unlock()
+
+	// clear the row
+	err := setCursorRow(event.row)
+	if err != nil {
+		fmt.Printf("failed to set cursor row: %s\n", err)
+		scr.closed = true
+		return false
+	}
+	// erase line (set mode=2)
+	_, err = fmt.Fprintf(scr.output, "\x1b[%dK", 2)
+	if err != nil {
+		fmt.Printf("failed to erase line: %s\n", err)
+		scr.closed = true
+		return false
+	}
+	// set cursor horizontal absolute position to 0
+	_, err = fmt.Fprintf(scr.output, "\x1b[%dG", 0)
+	if err != nil {
+		fmt.Printf("failed to set horizontal position: %s\n", err)
+		scr.closed = true
+		return false
+	}
+
+	// write the output
+	_, err = scr.output.Write(event.value)
+	if err != nil {
+		fmt.Printf("failed to write payload: %s\n", err)
+		scr.closed = true
+		return false
+	}
+	return true
+}
@@ -10,10 +10,11 @@ type ScreenEvent struct {
 }
 
 func newScreenEvent(line *Line) *ScreenEvent {
+	contents := line.render()
 	e := &ScreenEvent{
 		row:   line.row,
-		value: make([]byte, len(line.buffer)),
+		value: make([]byte, len(contents)),
 	}
-	copy(e.value, line.buffer)
+	copy(e.value, contents)
 	return e
 }
@@ -11,7 +11,7 @@ import (
 )
 
 var (
-	sigwinch = make(chan os.Signal)
+	sigwinch = make(chan os.Signal, 1)
 )
 
 type terminalSize struct {
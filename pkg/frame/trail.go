@@ -0,0 +1,108 @@
+package frame
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// TrailEntry describes a line that is being removed from the frame and is about to be written to the trail.
+type TrailEntry struct {
+	// Content is the final contents of the line
+	Content string
+	Key     string
+	Tags    []string
+	// Elapsed is how long the line was alive (from creation until removal)
+	Elapsed time.Duration
+}
+
+// TrailFormatter renders the trail text for a removed line. Returning false drops the line from the trail. Text
+// spanning several lines is written as one trail row per line.
+type TrailFormatter func(entry TrailEntry) (string, bool)
+
+// SetTrailFormatter overrides Config.TrailFormatter for this line.
+func (line *Line) SetTrailFormatter(formatter TrailFormatter) {
+	line.lock.Lock()
+	defer line.unlock()
+
+	line.trailFormatter = formatter
+}
+
+// TrailFormatter returns the formatter applied to the line: the one set with SetTrailFormatter, otherwise
+// Config.TrailFormatter of the frame (nil when neither is set).
+func (line *Line) TrailFormatter() TrailFormatter {
+	line.lock.RLock()
+	defer line.lock.RUnlock()
+	if line.trailFormatter == nil && line.frame != nil {
+		return line.frame.Config.TrailFormatter
+	}
+	return line.trailFormatter
+}
+
+func (line *Line) trailEntry() TrailEntry {
+	return TrailEntry{
+		Content: string(line.buffer),
+		Key:     line.key,
+		Tags:    line.Tags(),
+		Elapsed: time.Since(line.created),
+	}
+}
+
+// formatTrail returns the trail text for the given line (and false if the line should not be written to the trail).
+func (frame *Frame) formatTrail(line *Line) (string, bool) {
+	formatter := line.trailFormatter
+	if formatter == nil {
+		formatter = frame.Config.TrailFormatter
+	}
+
+	if formatter == nil {
+		return string(line.buffer), true
+	}
+	return formatter(line.trailEntry())
+}
+
+// appendTrailEntry queues the trail text of a removed line, one row per line of text.
+func (frame *Frame) appendTrailEntry(message string) {
+	for _, row := range strings.Split(message, "\n") {
+		frame.appendTrail(strings.TrimRight(row, "\r"))
+	}
+}
+
+// heldTrailEntry is a trail entry waiting on earlier lines to be removed (see Config.OrderedTrail).
+type heldTrailEntry struct {
+	seq     int
+	message string
+}
+
+func (frame *Frame) holdTrail(line *Line, message string) {
+	frame.heldTrail = append(frame.heldTrail, heldTrailEntry{
+		seq:     line.seq,
+		message: message,
+	})
+	sort.SliceStable(frame.heldTrail, func(i, j int) bool {
+		return frame.heldTrail[i].seq < frame.heldTrail[j].seq
+	})
+}
+
+// releaseTrail writes held trail entries (in creation order) that no longer have an earlier line remaining in
+// the frame body. When flushing, all held entries are written regardless. Returns true if any entries were written.
+func (frame *Frame) releaseTrail(flush bool) bool {
+	oldest := -1
+	for _, line := range frame.BodyLines {
+		if oldest < 0 || line.seq < oldest {
+			oldest = line.seq
+		}
+	}
+
+	released := false
+	for len(frame.heldTrail) > 0 {
+		entry := frame.heldTrail[0]
+		if !flush && oldest >= 0 && entry.seq > oldest {
+			break
+		}
+		frame.heldTrail = frame.heldTrail[1:]
+		frame.appendTrailEntry(entry.message)
+		released = true
+	}
+	return released
+}
@@ -0,0 +1,13 @@
+//go:build go1.21
+// +build go1.21
+
+package frame
+
+import (
+	"log/slog"
+)
+
+// SlogHandler returns a structured logging handler that writes text records to the trail above the frame.
+func (frame *Frame) SlogHandler(opts *slog.HandlerOptions) slog.Handler {
+	return slog.NewTextHandler(frame.TrailWriter(), opts)
+}
@@ -0,0 +1,28 @@
+//go:build go1.21
+// +build go1.21
+
+package frame
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func Test_Frame_SlogHandler(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 1, TrailOnRemove: true, ManualDraw: true})
+
+	logger := slog.New(frame.SlogHandler(&slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			// drop the time for a stable assertion
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+	logger.Info("compiled", "package", "pkg/frame")
+
+	if len(frame.trailRows) != 1 || frame.trailRows[0] != "level=INFO msg=compiled package=pkg/frame" {
+		t.Errorf("Frame.SlogHandler(): unexpected trail %q", frame.trailRows)
+	}
+}
@@ -0,0 +1,221 @@
+package frame
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Frame_TrailFormatter(t *testing.T) {
+	var entries []TrailEntry
+	frame := newTestFrame(Config{
+		TrailOnRemove: true,
+		TrailFormatter: func(entry TrailEntry) (string, bool) {
+			entries = append(entries, entry)
+			if entry.Key == "drop" {
+				return "", false
+			}
+			return fmt.Sprintf("✔ %s [%s]", entry.Content, strings.Join(entry.Tags, ",")), true
+		},
+		ManualDraw: true,
+	})
+
+	kept, _ := frame.AppendKeyed("pkg/foo")
+	kept.buffer = []byte("built pkg/foo")
+	kept.Tag("build")
+	kept.created = time.Now().Add(-3 * time.Second)
+
+	dropped, _ := frame.AppendKeyed("drop")
+	dropped.buffer = []byte("dropped")
+
+	frame.Remove(kept)
+	frame.Remove(dropped)
+
+	if len(entries) != 2 {
+		t.Fatalf("Config.TrailFormatter: expected 2 invocations, got %d", len(entries))
+	}
+
+	if entries[0].Key != "pkg/foo" || entries[0].Content != "built pkg/foo" || entries[0].Elapsed < 3*time.Second {
+		t.Errorf("Config.TrailFormatter: unexpected entry %+v", entries[0])
+	}
+
+	if len(frame.trailRows) != 1 || frame.trailRows[0] != "✔ built pkg/foo [build]" {
+		t.Errorf("Config.TrailFormatter: unexpected trail %v", frame.trailRows)
+	}
+
+	// the dropped line shrinks the frame instead of pushing it down
+	if frame.startIdx != 11 || frame.Height() != 0 {
+		t.Errorf("Config.TrailFormatter: unexpected frame position (start=%d height=%d)", frame.startIdx, frame.Height())
+	}
+}
+
+func Test_Line_SetTrailFormatter(t *testing.T) {
+	frame := newTestFrame(Config{
+		Lines:         2,
+		TrailOnRemove: true,
+		TrailFormatter: func(entry TrailEntry) (string, bool) {
+			return "frame:" + entry.Content, true
+		},
+		ManualDraw: true,
+	})
+
+	frame.BodyLines[0].buffer = []byte("a")
+	frame.BodyLines[1].buffer = []byte("b")
+	frame.BodyLines[1].SetTrailFormatter(func(entry TrailEntry) (string, bool) {
+		return "line:" + entry.Content, true
+	})
+
+	frame.Remove(frame.BodyLines[1])
+	frame.Remove(frame.BodyLines[0])
+
+	expected := []string{"line:b", "frame:a"}
+	if len(frame.trailRows) != len(expected) {
+		t.Fatalf("Line.SetTrailFormatter: expected %d trail rows, got %v", len(expected), frame.trailRows)
+	}
+	for idx, row := range expected {
+		if frame.trailRows[idx] != row {
+			t.Errorf("Line.SetTrailFormatter: expected '%s', got '%s'", row, frame.trailRows[idx])
+		}
+	}
+}
+
+func Test_Line_TrailFormatter(t *testing.T) {
+	frame := newTestFrame(Config{
+		Lines: 1,
+		TrailFormatter: func(entry TrailEntry) (string, bool) {
+			return "frame:" + entry.Content, true
+		},
+	})
+	line := frame.BodyLines[0]
+
+	// a line without a formatter of its own uses the one from the frame
+	if actual, _ := line.TrailFormatter()(TrailEntry{Content: "a"}); actual != "frame:a" {
+		t.Errorf("Line.TrailFormatter(): expected the frame formatter, got '%s'", actual)
+	}
+
+	line.SetTrailFormatter(func(entry TrailEntry) (string, bool) {
+		return "line:" + entry.Content, true
+	})
+	if actual, _ := line.TrailFormatter()(TrailEntry{Content: "a"}); actual != "line:a" {
+		t.Errorf("Line.TrailFormatter(): expected the line formatter, got '%s'", actual)
+	}
+
+	if NewLine(0, nil).TrailFormatter() != nil {
+		t.Errorf("Line.TrailFormatter(): expected no formatter for a line outside of a frame")
+	}
+}
+
+func Test_Frame_TrailFormatter_multiline(t *testing.T) {
+	frame := newTestFrame(Config{
+		Lines:         2,
+		TrailOnRemove: true,
+		ManualDraw:    true,
+	})
+	a, b := frame.BodyLines[0], frame.BodyLines[1]
+	a.buffer = []byte("a")
+	b.buffer = []byte("b")
+	b.SetTrailFormatter(func(entry TrailEntry) (string, bool) {
+		return entry.Content + "\ndetail 1\r\ndetail 2", true
+	})
+
+	frame.Remove(b)
+	frame.Remove(a)
+
+	expected := []string{"b", "detail 1", "detail 2", "a"}
+	if strings.Join(frame.trailRows, "|") != strings.Join(expected, "|") {
+		t.Errorf("Config.TrailFormatter: expected trail %q, got %q", expected, frame.trailRows)
+	}
+
+	// the frame moves down a row for every trail row
+	if frame.startIdx != 14 {
+		t.Errorf("Config.TrailFormatter: unexpected frame position %d", frame.startIdx)
+	}
+}
+
+func Test_Frame_OrderedTrail(t *testing.T) {
+	frame := newTestFrame(Config{
+		Lines:         3,
+		HeaderRows:    1,
+		TrailOnRemove: true,
+		OrderedTrail:  true,
+		ManualDraw:    true,
+	})
+	a, b, c := frame.BodyLines[0], frame.BodyLines[1], frame.BodyLines[2]
+	a.buffer = []byte("a")
+	b.buffer = []byte("b")
+	c.buffer = []byte("c")
+
+	frame.Remove(c)
+	if len(frame.trailRows) != 0 || len(frame.heldTrail) != 1 {
+		t.Errorf("Config.OrderedTrail: expected the last line to be held (trail=%v)", frame.trailRows)
+	}
+
+	frame.Remove(a)
+	if len(frame.trailRows) != 1 || frame.trailRows[0] != "a" {
+		t.Errorf("Config.OrderedTrail: expected only the first line to be written, got %v", frame.trailRows)
+	}
+
+	frame.Remove(b)
+	expected := []string{"a", "b", "c"}
+	if len(frame.trailRows) != len(expected) {
+		t.Fatalf("Config.OrderedTrail: expected %d trail rows, got %v", len(expected), frame.trailRows)
+	}
+	for idx, row := range expected {
+		if frame.trailRows[idx] != row {
+			t.Errorf("Config.OrderedTrail: expected '%s' at %d, got '%s'", row, idx, frame.trailRows[idx])
+		}
+	}
+
+	// the frame has been pushed down by one row per trail entry
+	if frame.startIdx != 13 || frame.HeaderLines[0].row != 13 {
+		t.Errorf("Config.OrderedTrail: unexpected frame position %d", frame.startIdx)
+	}
+}
+
+func Test_Frame_OrderedTrail_multiline(t *testing.T) {
+	frame := newTestFrame(Config{
+		Lines:         2,
+		TrailOnRemove: true,
+		OrderedTrail:  true,
+		ManualDraw:    true,
+	})
+	a, b := frame.BodyLines[0], frame.BodyLines[1]
+	a.buffer = []byte("a")
+	b.buffer = []byte("b")
+	b.SetTrailFormatter(func(entry TrailEntry) (string, bool) {
+		return entry.Content + "\ndetail", true
+	})
+
+	frame.Remove(b)
+	frame.Remove(a)
+
+	// all rows of a held entry are written together once it is released
+	expected := []string{"a", "b", "detail"}
+	if strings.Join(frame.trailRows, "|") != strings.Join(expected, "|") {
+		t.Errorf("Config.OrderedTrail: expected trail %q, got %q", expected, frame.trailRows)
+	}
+}
+
+func Test_Frame_OrderedTrail_FlushOnClose(t *testing.T) {
+	frame := newTestFrame(Config{
+		Lines:         2,
+		TrailOnRemove: true,
+		OrderedTrail:  true,
+		ManualDraw:    true,
+	})
+	frame.BodyLines[0].buffer = []byte("a")
+	frame.BodyLines[1].buffer = []byte("b")
+
+	frame.Remove(frame.BodyLines[1])
+	frame.Close()
+
+	if len(frame.heldTrail) != 0 {
+		t.Errorf("Frame.Close(): expected held trail entries to be flushed")
+	}
+
+	// the flushed entry is drawn above the frame, pushing it down a row
+	if frame.startIdx != 11 || frame.BodyLines[0].row != 11 {
+		t.Errorf("Frame.Close(): unexpected frame position %d", frame.startIdx)
+	}
+}
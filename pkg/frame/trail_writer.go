@@ -0,0 +1,88 @@
+package frame
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+)
+
+// TrailWriter is an io.Writer that writes each line of output to the trail above the frame. A trailing partial
+// line is held until it is completed (or flushed).
+type TrailWriter struct {
+	frame   *Frame
+	partial []byte
+	lock    sync.Mutex
+}
+
+// TrailWriter returns a writer that scrolls output above the frame (e.g. for application logging).
+func (frame *Frame) TrailWriter() *TrailWriter {
+	return &TrailWriter{
+		frame: frame,
+	}
+}
+
+// Logger returns a standard library logger that writes to the trail above the frame.
+func (frame *Frame) Logger(prefix string, flag int) *log.Logger {
+	return log.New(frame.TrailWriter(), prefix, flag)
+}
+
+func (writer *TrailWriter) Write(buff []byte) (int, error) {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	var rows []string
+	remaining := buff
+	for {
+		idx := bytes.IndexByte(remaining, '\n')
+		if idx < 0 {
+			break
+		}
+		writer.partial = append(writer.partial, remaining[:idx]...)
+		rows = append(rows, strings.TrimRight(string(writer.partial), "\r"))
+		writer.partial = writer.partial[:0]
+		remaining = remaining[idx+1:]
+	}
+	writer.partial = append(writer.partial, remaining...)
+
+	if err := writer.frame.appendTrailRows(rows); err != nil {
+		return 0, err
+	}
+	return len(buff), nil
+}
+
+// Flush writes any partial line held by the writer to the trail.
+func (writer *TrailWriter) Flush() error {
+	writer.lock.Lock()
+	defer writer.lock.Unlock()
+
+	if len(writer.partial) == 0 {
+		return nil
+	}
+	row := strings.TrimRight(string(writer.partial), "\r")
+	writer.partial = writer.partial[:0]
+	return writer.frame.appendTrailRows([]string{row})
+}
+
+// appendTrailRows writes the given rows to the trail with a single draw.
+func (frame *Frame) appendTrailRows(rows []string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	frame.lock.Lock()
+	defer frame.unlock()
+
+	if frame.closed {
+		return fmt.Errorf("frame is closed")
+	}
+
+	for _, row := range rows {
+		frame.appendTrail(row)
+	}
+	if frame.autoDraw {
+		frame.draw()
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package frame
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func Test_Frame_TrailWriter(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 1, TrailOnRemove: true, ManualDraw: true})
+	writer := frame.TrailWriter()
+
+	fmt.Fprint(writer, "first\r\nsec")
+	if !reflect.DeepEqual(frame.trailRows, []string{"first"}) {
+		t.Errorf("TrailWriter.Write(): expected a partial line to be held, got %q", frame.trailRows)
+	}
+
+	fmt.Fprint(writer, "ond\n\nthird")
+	writer.Flush()
+	writer.Flush()
+
+	expected := []string{"first", "second", "", "third"}
+	if !reflect.DeepEqual(frame.trailRows, expected) {
+		t.Errorf("TrailWriter.Flush(): expected trail %q, got %q", expected, frame.trailRows)
+	}
+
+	// the frame is pushed down by every row of the trail
+	if frame.startIdx != 14 {
+		t.Errorf("TrailWriter.Write(): expected the frame to start at row 14, got %d", frame.startIdx)
+	}
+
+	frame.Close()
+	if _, err := fmt.Fprintln(writer, "late"); err == nil {
+		t.Errorf("TrailWriter.Write(): expected an error once the frame is closed")
+	}
+}
+
+func Test_Frame_Logger(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 1, TrailOnRemove: true, ManualDraw: true})
+
+	logger := frame.Logger("[build] ", 0)
+	logger.Printf("compiled %d packages", 3)
+	logger.Print("done")
+
+	expected := []string{"[build] compiled 3 packages", "[build] done"}
+	if !reflect.DeepEqual(frame.trailRows, expected) {
+		t.Errorf("Frame.Logger(): expected trail %q, got %q", expected, frame.trailRows)
+	}
+}
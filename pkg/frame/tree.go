@@ -0,0 +1,221 @@
+package frame
+
+import (
+	"fmt"
+)
+
+// tree guides drawn in front of nested lines
+const (
+	treeBranch     = "├─ "
+	treeLastBranch = "└─ "
+	treePipe       = "│  "
+	treeBlank      = "   "
+)
+
+// AppendChild adds a new line nested under the given body line. The child is placed after the last
+// existing descendant of the parent and is rendered indented with tree guides.
+func (frame *Frame) AppendChild(parent *Line) (*Line, error) {
+	frame.lock.Lock()
+	defer frame.unlock()
+
+	return frame.appendChild(parent)
+}
+
+func (frame *Frame) appendChild(parent *Line) (*Line, error) {
+	if frame.IsClosed() {
+		return nil, fmt.Errorf("frame is closed")
+	}
+
+	section, parentIdx := frame.indexOf(parent)
+	if section != sectionBody || parentIdx < 0 {
+		return nil, fmt.Errorf("only body lines may have children")
+	}
+	index := parentIdx + len(parent.descendants()) + 1
+
+	newLine := frame.newLine(0)
+	newLine.parent = parent
+	parent.children = append(parent.children, newLine)
+
+	// children of a collapsed (or hidden) group start out folded away
+	if parent.collapsed || !parent.visible {
+		newLine.visible = false
+		newLine.folded = true
+		newLine.height = 0
+	}
+
+	frame.BodyLines = append(frame.BodyLines, nil)
+	copy(frame.BodyLines[index+1:], frame.BodyLines[index:])
+	frame.BodyLines[index] = newLine
+
+	frame.fit(newLine.height)
+
+	if frame.autoDraw {
+		frame.draw()
+	}
+
+	return newLine, nil
+}
+
+// refold hides or shows all descendants of the given line based on the collapsed state of each
+// ancestor, then re-positions the frame to account for the change in height.
+func (frame *Frame) refold(root *Line) error {
+	if frame.IsClosed() {
+		return fmt.Errorf("frame is closed")
+	}
+
+	before := frame.Height()
+
+	var walk func(parent *Line, fold bool)
+	walk = func(parent *Line, fold bool) {
+		for _, child := range parent.children {
+			if fold && child.visible {
+				child.folded = true
+				child.visible = false
+				child.height = 0
+			} else if !fold && child.folded {
+				child.folded = false
+				child.visible = true
+				child.height = 1
+			}
+			walk(child, fold || child.collapsed || !child.visible)
+		}
+	}
+	walk(root, root.collapsed || !root.visible)
+
+	frame.fit(frame.Height() - before)
+
+	if frame.autoDraw {
+		frame.draw()
+	}
+
+	return nil
+}
+
+// AppendChild adds a new line nested under this line (see Frame.AppendChild).
+func (line *Line) AppendChild() (*Line, error) {
+	line.lock.Lock()
+	defer line.unlock()
+
+	if line.frame == nil {
+		return nil, fmt.Errorf("line is not attached to a frame")
+	}
+	return line.frame.appendChild(line)
+}
+
+// Collapse folds away all descendants of the line, leaving only the line itself on the screen.
+func (line *Line) Collapse() error {
+	line.lock.Lock()
+	defer line.unlock()
+
+	return line.setCollapsed(true)
+}
+
+// Expand shows all descendants of the line again (except those under a nested collapsed group).
+func (line *Line) Expand() error {
+	line.lock.Lock()
+	defer line.unlock()
+
+	return line.setCollapsed(false)
+}
+
+func (line *Line) setCollapsed(collapsed bool) error {
+	line.collapsed = collapsed
+	if line.frame == nil {
+		return nil
+	}
+	return line.frame.refold(line)
+}
+
+func (line *Line) IsCollapsed() bool {
+	return line.collapsed
+}
+
+func (line *Line) Parent() *Line {
+	return line.parent
+}
+
+func (line *Line) Children() []*Line {
+	children := make([]*Line, len(line.children))
+	copy(children, line.children)
+	return children
+}
+
+// Depth is the number of ancestors of the line (top-level lines have a depth of 0).
+func (line *Line) Depth() int {
+	depth := 0
+	for ancestor := line.parent; ancestor != nil; ancestor = ancestor.parent {
+		depth++
+	}
+	return depth
+}
+
+// descendants returns all lines nested under this line in the order they appear in the frame.
+func (line *Line) descendants() []*Line {
+	var result []*Line
+	for _, child := range line.children {
+		result = append(result, child)
+		result = append(result, child.descendants()...)
+	}
+	return result
+}
+
+// detach removes the line from the children of its parent.
+func (line *Line) detach() {
+	if line.parent == nil {
+		return
+	}
+	siblings := line.parent.children
+	for idx, sibling := range siblings {
+		if sibling == line {
+			line.parent.children = append(siblings[:idx], siblings[idx+1:]...)
+			break
+		}
+	}
+	line.parent = nil
+}
+
+func (line *Line) isLastSibling() bool {
+	if line.parent == nil {
+		return true
+	}
+	siblings := line.parent.children
+	for idx := len(siblings) - 1; idx >= 0; idx-- {
+		if siblings[idx] == line {
+			return true
+		}
+		if siblings[idx].shown() {
+			return false
+		}
+	}
+	return true
+}
+
+// treePrefix returns the guides drawn in front of a nested line.
+func (line *Line) treePrefix() string {
+	if line.parent == nil {
+		return ""
+	}
+
+	prefix := treeBranch
+	if line.isLastSibling() {
+		prefix = treeLastBranch
+	}
+
+	for ancestor := line.parent; ancestor.parent != nil; ancestor = ancestor.parent {
+		if ancestor.isLastSibling() {
+			prefix = treeBlank + prefix
+		} else {
+			prefix = treePipe + prefix
+		}
+	}
+	return prefix
+}
+
+// render returns the line contents as drawn on the screen.
+func (line *Line) render() []byte {
+	prefix := line.treePrefix()
+	if prefix == "" {
+		return line.buffer
+	}
+	return append([]byte(prefix), line.buffer...)
+}
@@ -0,0 +1,174 @@
+package frame
+
+import (
+	"testing"
+)
+
+func Test_Frame_AppendChild(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 2, HeaderRows: 1, FooterRows: 1, TrailOnRemove: true, ManualDraw: true})
+	parent := frame.BodyLines[0]
+
+	first, err := parent.AppendChild()
+	if err != nil {
+		t.Fatalf("Line.AppendChild(): expected no error, got %v", err)
+	}
+	second, _ := parent.AppendChild()
+	nested, _ := first.AppendChild()
+
+	expectedOrder := []*Line{parent, first, nested, second, frame.BodyLines[4]}
+	for idx, line := range expectedOrder {
+		if frame.BodyLines[idx] != line {
+			t.Errorf("Frame.AppendChild(): unexpected line at index %d: %v", idx, frame.BodyLines[idx])
+		}
+		if expectedRow := 11 + idx; line.row != expectedRow {
+			t.Errorf("Frame.AppendChild(): expected line %d at row %d, got %d", idx, expectedRow, line.row)
+		}
+	}
+
+	if frame.FooterLines[0].row != 16 {
+		t.Errorf("Frame.AppendChild(): expected footer at row 16, got %d", frame.FooterLines[0].row)
+	}
+
+	if nested.Depth() != 2 || nested.Parent() != first {
+		t.Errorf("Frame.AppendChild(): unexpected nesting for %v (depth=%d)", nested, nested.Depth())
+	}
+
+	if _, err := frame.AppendChild(frame.HeaderLines[0]); err == nil {
+		t.Errorf("Frame.AppendChild(): expected an error when nesting under a header")
+	}
+}
+
+func Test_Line_treePrefix(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 1, HeaderRows: 1, FooterRows: 1, TrailOnRemove: true, ManualDraw: true})
+	parent := frame.BodyLines[0]
+	first, _ := parent.AppendChild()
+	nested, _ := first.AppendChild()
+	second, _ := parent.AppendChild()
+	lastNested, _ := second.AppendChild()
+
+	tables := []struct {
+		line     *Line
+		expected string
+	}{
+		{parent, ""},
+		{first, "├─ "},
+		{nested, "│  └─ "},
+		{second, "└─ "},
+		{lastNested, "   └─ "},
+	}
+
+	for idx, table := range tables {
+		table.line.buffer = []byte("x")
+		actual := string(table.line.render())
+		if actual != table.expected+"x" {
+			t.Errorf("Line.render(): case %d expected '%sx', got '%s'", idx, table.expected, actual)
+		}
+	}
+}
+
+func Test_Line_CollapseExpand(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 2, HeaderRows: 1, FooterRows: 1, TrailOnRemove: true, ManualDraw: true})
+	parent := frame.BodyLines[0]
+	first, _ := parent.AppendChild()
+	first.AppendChild()
+	parent.AppendChild()
+	footer := frame.FooterLines[0]
+	last := frame.BodyLines[4]
+
+	err := parent.Collapse()
+	if err != nil {
+		t.Fatalf("Line.Collapse(): expected no error, got %v", err)
+	}
+
+	if frame.Height() != 4 {
+		t.Errorf("Line.Collapse(): expected height 4, got %d", frame.Height())
+	}
+	if last.row != 12 || footer.row != 13 {
+		t.Errorf("Line.Collapse(): unexpected rows (last=%d footer=%d)", last.row, footer.row)
+	}
+	if !contains(frame.clearRows, 14) || !contains(frame.clearRows, 16) {
+		t.Errorf("Line.Collapse(): expected vacated rows to be cleared, got %v", frame.clearRows)
+	}
+
+	// a child added to a collapsed group is folded away
+	added, _ := parent.AppendChild()
+	if added.visible || frame.Height() != 4 {
+		t.Errorf("Line.AppendChild(): expected child of collapsed group to be hidden")
+	}
+
+	first.Collapse()
+	parent.Expand()
+
+	// the nested group remains collapsed
+	if frame.Height() != 7 {
+		t.Errorf("Line.Expand(): expected height 7, got %d", frame.Height())
+	}
+	if last.row != 15 || footer.row != 16 {
+		t.Errorf("Line.Expand(): unexpected rows (last=%d footer=%d)", last.row, footer.row)
+	}
+}
+
+func Test_Line_HideGroup(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 2, HeaderRows: 1, FooterRows: 1, TrailOnRemove: true, ManualDraw: true})
+	parent := frame.BodyLines[0]
+	first, _ := parent.AppendChild()
+	nested, _ := first.AppendChild()
+	second, _ := parent.AppendChild()
+	second.Hide()
+	last := frame.BodyLines[4]
+	footer := frame.FooterLines[0]
+
+	err := parent.Hide()
+	if err != nil {
+		t.Fatalf("Line.Hide(): expected no error, got %v", err)
+	}
+
+	// the whole group leaves the screen
+	if first.visible || nested.visible || frame.Height() != 3 {
+		t.Errorf("Line.Hide(): expected the children to be hidden with the parent (height=%d)", frame.Height())
+	}
+	if last.row != 11 || footer.row != 12 {
+		t.Errorf("Line.Hide(): unexpected rows (last=%d footer=%d)", last.row, footer.row)
+	}
+
+	err = parent.Show()
+	if err != nil {
+		t.Fatalf("Line.Show(): expected no error, got %v", err)
+	}
+
+	// a child that was hidden on its own stays hidden
+	if !first.visible || !nested.visible || second.visible || frame.Height() != 6 {
+		t.Errorf("Line.Show(): expected the folded children to be shown again (height=%d)", frame.Height())
+	}
+	if last.row != 14 || footer.row != 15 {
+		t.Errorf("Line.Show(): unexpected rows (last=%d footer=%d)", last.row, footer.row)
+	}
+}
+
+func Test_Frame_RemoveGroup(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 2, HeaderRows: 1, FooterRows: 1, TrailOnRemove: true, ManualDraw: true})
+	parent := frame.BodyLines[0]
+	parent.buffer = []byte("parent")
+	first, _ := parent.AppendChild()
+	first.AppendChild()
+	parent.AppendChild()
+	last := frame.BodyLines[4]
+
+	err := frame.Remove(parent)
+	if err != nil {
+		t.Fatalf("Frame.Remove(): expected no error, got %v", err)
+	}
+
+	if len(frame.BodyLines) != 1 || frame.BodyLines[0] != last {
+		t.Fatalf("Frame.Remove(): expected only the last line to remain, got %v", frame.BodyLines)
+	}
+
+	if len(frame.trailRows) != 1 || frame.trailRows[0] != "parent" {
+		t.Errorf("Frame.Remove(): expected a single trail entry, got %v", frame.trailRows)
+	}
+
+	// the trail entry pushes the frame down by a row
+	if last.row != 12 || frame.FooterLines[0].row != 13 {
+		t.Errorf("Frame.Remove(): unexpected rows (last=%d footer=%d)", last.row, frame.FooterLines[0].row)
+	}
+}
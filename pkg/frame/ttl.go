@@ -0,0 +1,100 @@
+package frame
+
+import (
+	"fmt"
+	"time"
+)
+
+// AppendWithTTL adds a new body line to the end of the frame that is removed once the given duration
+// has passed (see Line.ExpireAfter).
+func (frame *Frame) AppendWithTTL(ttl time.Duration) (*Line, error) {
+	if frame.IsClosed() {
+		return nil, fmt.Errorf("frame is closed")
+	}
+
+	frame.lock.Lock()
+	defer frame.unlock()
+
+	line, err := frame.append()
+	if err != nil {
+		return nil, err
+	}
+	line.expireAfter(ttl)
+
+	return line, nil
+}
+
+// ExpireAfter removes the line from its frame once the given duration has passed, the same as calling
+// Line.Remove (including any trail rules). Calling this again replaces any existing deadline.
+func (line *Line) ExpireAfter(ttl time.Duration) {
+	line.lock.Lock()
+	defer line.unlock()
+
+	line.expireAfter(ttl)
+}
+
+// ExtendExpiry pushes back the pending expiration of the line by the given duration. Returns false if
+// the line has no pending expiration.
+func (line *Line) ExtendExpiry(duration time.Duration) bool {
+	line.lock.Lock()
+	defer line.unlock()
+
+	if line.expiry == nil {
+		return false
+	}
+	line.expireAfter(time.Until(line.deadline.Add(duration)))
+	return true
+}
+
+// CancelExpiry stops any pending expiration of the line. Returns false if the line has no pending expiration.
+func (line *Line) CancelExpiry() bool {
+	line.lock.Lock()
+	defer line.unlock()
+
+	return line.cancelExpiry()
+}
+
+// Deadline returns when the line expires (and false if the line has no pending expiration).
+func (line *Line) Deadline() (time.Time, bool) {
+	line.lock.RLock()
+	defer line.lock.RUnlock()
+
+	return line.deadline, line.expiry != nil
+}
+
+func (line *Line) expireAfter(ttl time.Duration) {
+	line.cancelExpiry()
+
+	// the generation guards against a timer that fired while a new deadline was being set
+	line.expiryGen++
+	generation := line.expiryGen
+	line.deadline = time.Now().Add(ttl)
+	line.expiry = time.AfterFunc(ttl, func() {
+		line.expire(generation)
+	})
+}
+
+func (line *Line) cancelExpiry() bool {
+	if line.expiry == nil {
+		return false
+	}
+	line.expiry.Stop()
+	line.expiry = nil
+	line.deadline = time.Time{}
+	return true
+}
+
+func (line *Line) expire(generation int) {
+	line.lock.Lock()
+	defer line.unlock()
+
+	if line.expiry == nil || generation != line.expiryGen {
+		return
+	}
+	line.expiry = nil
+
+	if line.frame != nil && !line.frame.IsClosed() {
+		// there is no caller to report to, an expired line that is already gone is not a problem
+		_ = line.frame.remove(line, false)
+	}
+}
@@ -0,0 +1,82 @@
+package frame
+
+import (
+	"testing"
+	"time"
+)
+
+func waitForRemoval(frame *Frame, line *Line, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		frame.lock.RLock()
+		_, idx := frame.indexOf(line)
+		frame.lock.RUnlock()
+		if idx < 0 {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return false
+}
+
+func Test_Frame_AppendWithTTL(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 1, TrailOnRemove: true, ManualDraw: true})
+
+	line, err := frame.AppendWithTTL(20 * time.Millisecond)
+	if err != nil {
+		t.Fatalf("Frame.AppendWithTTL(): expected no error, got %v", err)
+	}
+	line.buffer = []byte("cache hit")
+
+	if _, pending := line.Deadline(); !pending {
+		t.Errorf("Frame.AppendWithTTL(): expected a pending expiration")
+	}
+
+	if !waitForRemoval(frame, line, time.Second) {
+		t.Fatalf("Frame.AppendWithTTL(): expected line to be removed")
+	}
+
+	if !line.IsClosed() || len(frame.trailRows) != 1 || frame.trailRows[0] != "cache hit" {
+		t.Errorf("Frame.AppendWithTTL(): expected the expired line to follow the trail rules (trail=%v)", frame.trailRows)
+	}
+}
+
+func Test_Line_CancelExpiry(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 1, TrailOnRemove: true, ManualDraw: true})
+	line := frame.BodyLines[0]
+
+	line.ExpireAfter(10 * time.Millisecond)
+	if !line.CancelExpiry() {
+		t.Errorf("Line.CancelExpiry(): expected a pending expiration to be cancelled")
+	}
+
+	if waitForRemoval(frame, line, 50*time.Millisecond) {
+		t.Errorf("Line.CancelExpiry(): expected line to remain in the frame")
+	}
+
+	if line.CancelExpiry() || line.ExtendExpiry(time.Second) {
+		t.Errorf("Line.CancelExpiry(): expected no pending expiration")
+	}
+}
+
+func Test_Line_ExtendExpiry(t *testing.T) {
+	frame := newTestFrame(Config{Lines: 1, TrailOnRemove: true, ManualDraw: true})
+	line := frame.BodyLines[0]
+
+	line.ExpireAfter(time.Minute)
+	before, _ := line.Deadline()
+
+	if !line.ExtendExpiry(time.Minute) {
+		t.Fatalf("Line.ExtendExpiry(): expected a pending expiration to be extended")
+	}
+	after, _ := line.Deadline()
+
+	if delta := after.Sub(before); delta < 59*time.Second || delta > 61*time.Second {
+		t.Errorf("Line.ExtendExpiry(): expected the deadline to move by a minute, moved by %v", delta)
+	}
+
+	frame.Remove(line)
+	if _, pending := line.Deadline(); pending {
+		t.Errorf("Frame.Remove(): expected the pending expiration to be cancelled")
+	}
+}
@@ -0,0 +1,117 @@
+package recipe
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+// ChildQueue runs work nested under the line of a running worker (e.g. the files of a package being compiled).
+// Each item is drawn as an indented line under the parent line and is dropped from the trail when it finishes,
+// folding back into the parent line. When used from within a WorkQueue worker, child work shares the concurrency
+// limit of the queue: the worker lends its own slot to its children while it waits on them.
+type ChildQueue struct {
+	lines  lineSource
+	queue  []*queuedWork
+	active *scheduler
+	list   *workList
+	lock   sync.Mutex
+	// FailFast cancels the context of running child work and skips any child work not yet started on the first failure
+	FailFast bool
+}
+
+// childLines hands out lines nested under a parent line that leave no trail entry behind
+type childLines struct {
+	parent *frame.Line
+}
+
+func (lines childLines) Append() (*frame.Line, error) {
+	line, err := lines.parent.AppendChild()
+	if err != nil {
+		return nil, err
+	}
+	line.SetTrailFormatter(func(frame.TrailEntry) (string, bool) {
+		return "", false
+	})
+	return line, nil
+}
+
+func (lines childLines) Remove(line *frame.Line) error {
+	return line.Remove()
+}
+
+func NewChildQueue(parent *frame.Line) *ChildQueue {
+	return &ChildQueue{
+		lines: childLines{parent: parent},
+	}
+}
+
+// AddWork queues child work with the default priority (zero).
+func (cq *ChildQueue) AddWork(worker Worker) error {
+	return cq.AddWorkWithPriority(worker, 0)
+}
+
+// AddWorkWithPriority queues child work (see WorkQueue.AddWorkWithPriority).
+func (cq *ChildQueue) AddWorkWithPriority(worker Worker, priority int) error {
+	cq.lock.Lock()
+	defer cq.lock.Unlock()
+
+	item := &queuedWork{
+		index:    len(cq.queue),
+		priority: priority,
+		worker:   worker,
+	}
+
+	if cq.active != nil {
+		if err := cq.active.push(cq.list, item); err != nil {
+			return err
+		}
+	}
+	cq.queue = append(cq.queue, item)
+	return nil
+}
+
+// Work runs all queued child work and blocks until every item has finished. The context should be the one given
+// to the calling worker (otherwise child work runs one item at a time). The results are in the order the work was
+// added; the returned error is a *WorkError when any item failed.
+func (cq *ChildQueue) Work(ctx context.Context) ([]Result, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sched, shared := ctx.Value(schedulerKey{}).(*scheduler)
+	if !shared {
+		sched = newScheduler(1, 0)
+	}
+
+	cq.lock.Lock()
+	list := &workList{}
+	for _, item := range cq.queue {
+		sched.push(list, item)
+	}
+	cq.active = sched
+	cq.list = list
+	cq.lock.Unlock()
+
+	if shared {
+		sched.lend()
+	}
+
+	stop := make(chan struct{})
+	go sched.wake(ctx, stop)
+
+	outcomes := runList(ctx, cancel, sched, list, true, cq.lines, nil, cq.FailFast)
+	close(stop)
+
+	if shared {
+		sched.reclaim()
+	}
+
+	cq.lock.Lock()
+	cq.active = nil
+	cq.list = nil
+	queue := cq.queue
+	cq.lock.Unlock()
+
+	return collectResults(queue, outcomes)
+}
@@ -0,0 +1,117 @@
+package recipe
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+func newTestChildQueue(lines lineSource) *ChildQueue {
+	return &ChildQueue{lines: lines}
+}
+
+func Test_ChildQueue_sharesConcurrency(t *testing.T) {
+	wq := NewWorkQueue(2)
+	lines := newTestLines()
+
+	var running, peak int64
+	child := WorkerFunc(func(context.Context, *frame.Line) error {
+		current := atomic.AddInt64(&running, 1)
+		defer atomic.AddInt64(&running, -1)
+		for {
+			observed := atomic.LoadInt64(&peak)
+			if current <= observed || atomic.CompareAndSwapInt64(&peak, observed, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+
+	var childErr error
+	wq.AddWork(WorkerFunc(func(ctx context.Context, line *frame.Line) error {
+		cq := newTestChildQueue(lines)
+		for idx := 0; idx < 6; idx++ {
+			cq.AddWork(child)
+		}
+		_, childErr = cq.Work(ctx)
+		return childErr
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		wq.run(context.Background(), lines, nil)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("ChildQueue.Work(): expected child work to finish")
+	}
+
+	// the parent lends its slot, so children may use the entire budget
+	if peak != 2 {
+		t.Errorf("ChildQueue.Work(): expected 2 concurrent children, got %d", peak)
+	}
+	if childErr != nil {
+		t.Errorf("ChildQueue.Work(): expected no error, got %v", childErr)
+	}
+}
+
+func Test_ChildQueue_nested(t *testing.T) {
+	wq := NewWorkQueue(1)
+	lines := newTestLines()
+
+	var leaves int64
+	var nest func(depth int) Worker
+	nest = func(depth int) Worker {
+		return WorkerFunc(func(ctx context.Context, line *frame.Line) error {
+			if depth == 0 {
+				atomic.AddInt64(&leaves, 1)
+				return nil
+			}
+			cq := newTestChildQueue(lines)
+			cq.AddWork(nest(depth - 1))
+			cq.AddWork(nest(depth - 1))
+			_, err := cq.Work(ctx)
+			return err
+		})
+	}
+	wq.AddWork(nest(3))
+	wq.AddWork(nest(1))
+
+	done := make(chan struct{})
+	go func() {
+		wq.run(context.Background(), lines, nil)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("ChildQueue.Work(): expected nested work with a concurrency of one not to deadlock")
+	}
+
+	if leaves != 10 {
+		t.Errorf("ChildQueue.Work(): expected 10 leaves to run, got %d", leaves)
+	}
+}
+
+func Test_ChildQueue_withoutQueue(t *testing.T) {
+	cq := newTestChildQueue(newTestLines())
+	cq.FailFast = true
+	cq.AddWork(WorkerFunc(func(context.Context, *frame.Line) error {
+		return fmt.Errorf("boom")
+	}))
+	cq.AddWork(WorkerFunc(noopTask))
+
+	results, err := cq.Work(context.Background())
+	if err == nil || results[1].Err != context.Canceled {
+		t.Errorf("ChildQueue.Work(): expected the second item to be cancelled, got %v", results)
+	}
+}
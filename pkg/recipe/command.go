@@ -0,0 +1,187 @@
+package recipe
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/wagoodman/jotframe/pkg/component"
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+// CommandOptions configures how RunCommand draws a running command.
+type CommandOptions struct {
+	// Title is shown next to the spinner (the command line is used when empty)
+	Title string
+	// TailRows is the number of output lines shown under the title. When zero only the latest output line is
+	// shown, on the title line itself.
+	TailRows int
+}
+
+// RunCommand runs the given command on a new line in the frame, showing a spinner, the elapsed time and the
+// latest output while it runs. Stdout and stderr of the command are replaced. When the command exits the line is
+// ended with a ✔ or ✘ and the exit code. With TrailOnRemove the line is then removed, leaving it in the trail
+// followed by the full output if the command failed (as part of the same trail entry, so that the output stays
+// under its result with Config.OrderedTrail). Otherwise the finished command stays in the frame, where the
+// tail window (if any) collapses on success or grows to the full output on failure.
+func RunCommand(fr *frame.Frame, cmd *exec.Cmd, options CommandOptions) error {
+	return runCommand(fr, fr.Config.TrailOnRemove, cmd, options)
+}
+
+func runCommand(lines lineSource, trail bool, cmd *exec.Cmd, options CommandOptions) error {
+	title := options.Title
+	if title == "" {
+		title = strings.Join(cmd.Args, " ")
+	}
+
+	line, err := lines.Append()
+	if err != nil {
+		return err
+	}
+
+	output := &commandOutput{
+		title: title,
+		tail:  options.TailRows > 0,
+	}
+	output.spinner = component.NewSpinner(line, nil, title)
+	output.elapsed = output.spinner.ShowElapsed(output.message())
+
+	if output.tail {
+		output.window, err = component.NewLogTail(line, options.TailRows)
+		if err != nil {
+			output.spinner.Stop()
+			lines.Remove(line)
+			return err
+		}
+	}
+
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	runErr := cmd.Run()
+
+	summary := fmt.Sprintf("%s (%s after %s)", title, exitStatus(cmd, runErr), component.FormatDuration(output.elapsed.Duration()))
+	if runErr == nil {
+		output.spinner.Success(summary)
+	} else {
+		output.spinner.Fail(summary)
+	}
+
+	if !trail {
+		if output.tail {
+			output.window.Close(runErr)
+		}
+		return runErr
+	}
+
+	// the trail takes the place of the window, so there is no need to grow it first
+	if output.tail {
+		output.window.Close(nil)
+	}
+	if runErr != nil {
+		output.trailOutput(line)
+	}
+	if err := lines.Remove(line); err != nil {
+		return err
+	}
+
+	return runErr
+}
+
+// exitStatus describes how the command ended.
+func exitStatus(cmd *exec.Cmd, err error) string {
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return fmt.Sprintf("exit %d", exitErr.ExitCode())
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("exit %d", cmd.ProcessState.ExitCode())
+}
+
+// commandOutput captures everything written by a command and keeps the title line (and tail window) current.
+type commandOutput struct {
+	title   string
+	tail    bool
+	spinner *component.Spinner
+	elapsed *component.Elapsed
+	window  *component.LogTail
+	buffer  bytes.Buffer
+	latest  string
+	lock    sync.Mutex
+}
+
+func (output *commandOutput) Write(buff []byte) (int, error) {
+	output.lock.Lock()
+	defer output.lock.Unlock()
+
+	output.buffer.Write(buff)
+
+	if output.tail {
+		return output.window.Write(buff)
+	}
+
+	if latest := lastLine(buff); latest != "" {
+		output.latest = latest
+		output.elapsed.SetMessage(output.message())
+	}
+	return len(buff), nil
+}
+
+// message is the text shown after the spinner glyph, with a placeholder for the elapsed time.
+func (output *commandOutput) message() string {
+	message := output.title + " (%s)"
+	if output.latest != "" {
+		message += ": " + output.latest
+	}
+	return message
+}
+
+// trailOutput adds all captured output to the trail entry of the line, after the (formatted) result.
+func (output *commandOutput) trailOutput(line *frame.Line) {
+	captured := output.lines()
+	if len(captured) == 0 {
+		return
+	}
+
+	formatter := line.TrailFormatter()
+	line.SetTrailFormatter(func(entry frame.TrailEntry) (string, bool) {
+		if formatter != nil {
+			content, keep := formatter(entry)
+			if !keep {
+				return "", false
+			}
+			entry.Content = content
+		}
+		return entry.Content + "\n" + strings.Join(captured, "\n"), true
+	})
+}
+
+// lines returns all captured output split into lines.
+func (output *commandOutput) lines() []string {
+	output.lock.Lock()
+	defer output.lock.Unlock()
+
+	contents := strings.TrimRight(output.buffer.String(), "\n")
+	if contents == "" {
+		return nil
+	}
+	lines := strings.Split(contents, "\n")
+	for idx, line := range lines {
+		lines[idx] = strings.TrimRight(line, "\r")
+	}
+	return lines
+}
+
+// lastLine returns the last non-empty line in the given output.
+func lastLine(buff []byte) string {
+	lines := strings.Split(string(buff), "\n")
+	for idx := len(lines) - 1; idx >= 0; idx-- {
+		if line := strings.TrimSpace(lines[idx]); line != "" {
+			return line
+		}
+	}
+	return ""
+}
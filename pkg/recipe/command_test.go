@@ -0,0 +1,131 @@
+package recipe
+
+import (
+	"os/exec"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+// testCommandLines records the lines of a command
+type testCommandLines struct {
+	*testLines
+	lines []*frame.Line
+}
+
+func (lines *testCommandLines) Append() (*frame.Line, error) {
+	line, err := lines.testLines.Append()
+	lines.lines = append(lines.lines, line)
+	return line, err
+}
+
+// trailOutput returns the rows following the result in the trail entry of the line.
+func trailOutput(line *frame.Line) []string {
+	formatter := line.TrailFormatter()
+	if formatter == nil {
+		return nil
+	}
+	entry, _ := formatter(frame.TrailEntry{Content: line.Contents()})
+	return strings.Split(entry, "\n")[1:]
+}
+
+func Test_runCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	tables := []struct {
+		name     string
+		args     []string
+		trail    bool
+		err      string
+		contents string
+		output   []string
+		removed  int
+	}{
+		{"success", []string{"true"}, true, "", "✔ true (exit 0 after %s)", nil, 1},
+		{"failure", []string{"false"}, true, "exit status 1", "✘ false (exit 1 after %s)", nil, 1},
+		{"failure output", []string{"sh", "-c", "echo compiling; echo 'error: missing semicolon' >&2; exit 3"}, true, "exit status 3", "✘ build (exit 3 after %s)", []string{"compiling", "error: missing semicolon"}, 1},
+		{"without trail", []string{"sh", "-c", "echo compiling; exit 3"}, false, "exit status 3", "✘ build (exit 3 after %s)", nil, 0},
+	}
+
+	for _, table := range tables {
+		lines := &testCommandLines{testLines: newTestLines()}
+		title := ""
+		if len(table.args) > 1 {
+			title = "build"
+		}
+
+		err := runCommand(lines, table.trail, exec.Command(table.args[0], table.args[1:]...), CommandOptions{Title: title})
+
+		if (err == nil && table.err != "") || (err != nil && err.Error() != table.err) {
+			t.Errorf("%s: expected error '%s', got %v", table.name, table.err, err)
+		}
+		if len(lines.lines) != 1 {
+			t.Fatalf("%s: expected a single line, got %d", table.name, len(lines.lines))
+		}
+		// the elapsed time depends on how quickly the command ran
+		pattern := "^" + strings.Replace(regexp.QuoteMeta(table.contents), "%s", "[0-9hms]+", 1) + "$"
+		if actual := lines.lines[0].Contents(); !regexp.MustCompile(pattern).MatchString(actual) {
+			t.Errorf("%s: expected line '%s', got '%s'", table.name, table.contents, actual)
+		}
+		if !lines.lines[0].IsClosed() {
+			t.Errorf("%s: expected the line to be closed", table.name)
+		}
+		if actual := trailOutput(lines.lines[0]); strings.Join(actual, "|") != strings.Join(table.output, "|") {
+			t.Errorf("%s: expected trail output %q, got %q", table.name, table.output, actual)
+		}
+		if lines.removed != table.removed {
+			t.Errorf("%s: expected %d removed lines, got %d", table.name, table.removed, lines.removed)
+		}
+	}
+}
+
+func Test_runCommand_notFound(t *testing.T) {
+	lines := &testCommandLines{testLines: newTestLines()}
+
+	err := runCommand(lines, true, exec.Command("jotframe-does-not-exist"), CommandOptions{})
+	if err == nil {
+		t.Fatalf("runCommand(): expected an error for a missing command")
+	}
+	if actual := lines.lines[0].Contents(); !strings.HasPrefix(actual, "✘ jotframe-does-not-exist (exec: ") {
+		t.Errorf("runCommand(): unexpected line '%s'", actual)
+	}
+}
+
+func Test_lastLine(t *testing.T) {
+	tables := []struct {
+		output   string
+		expected string
+	}{
+		{"building\n", "building"},
+		{"first\nsecond\n\n  \n", "second"},
+		{"  partial", "partial"},
+		{"\n\n", ""},
+	}
+
+	for _, table := range tables {
+		if actual := lastLine([]byte(table.output)); actual != table.expected {
+			t.Errorf("lastLine(%q): expected '%s', got '%s'", table.output, table.expected, actual)
+		}
+	}
+}
+
+func Test_commandOutput_lines(t *testing.T) {
+	output := &commandOutput{}
+	output.buffer.WriteString("compiling\r\nerror: missing semicolon\n")
+
+	expected := []string{"compiling", "error: missing semicolon"}
+	if actual := output.lines(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("commandOutput.lines(): expected %q, got %q", expected, actual)
+	}
+
+	output.latest = "compiling"
+	output.title = "make"
+	if actual := output.message(); actual != "make (%s): compiling" {
+		t.Errorf("commandOutput.message(): unexpected message '%s'", actual)
+	}
+}
@@ -0,0 +1,301 @@
+package recipe
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+
+	"github.com/k0kubun/go-ansi"
+)
+
+// TaskResult is the outcome of a single task in a TaskGraph. Tasks that were skipped because a dependency failed
+// (or because the graph was cancelled before they could start) have Skipped set along with the reason in Err.
+type TaskResult struct {
+	Name    string
+	Err     error
+	Skipped bool
+	// Cancelled is set when the graph was cancelled before the task finished (it was still running or never started)
+	Cancelled bool
+}
+
+// TaskGraph runs tasks in dependency order, running independent tasks concurrently (up to a limit). A task only
+// has a line in the frame while it is ready to run or running.
+type TaskGraph struct {
+	maxConcurrent int64
+	tasks         map[string]*task
+	names         []string
+	// FailFast skips every task not yet started on the first failure (otherwise only dependents are skipped)
+	FailFast bool
+	// HandleInterrupt cancels the graph on Ctrl-C (a second Ctrl-C exits immediately)
+	HandleInterrupt bool
+}
+
+type task struct {
+	name       string
+	worker     Worker
+	dependsOn  []string
+	dependents []*task
+	waiting    int
+	line       *frame.Line
+	result     *TaskResult
+}
+
+// taskDone is sent by a task goroutine once its worker returns
+type taskDone struct {
+	task *task
+	err  error
+}
+
+func NewTaskGraph(maxConcurrent int64) *TaskGraph {
+	return &TaskGraph{
+		maxConcurrent: maxConcurrent,
+		tasks:         make(map[string]*task),
+	}
+}
+
+// AddTask declares a task that runs only once all of the named dependencies have succeeded. Dependencies may be
+// declared after the task that depends on them.
+func (graph *TaskGraph) AddTask(name string, worker Worker, dependsOn ...string) error {
+	if name == "" {
+		return fmt.Errorf("task name must not be empty")
+	}
+	if _, exists := graph.tasks[name]; exists {
+		return fmt.Errorf("task '%s' is already declared", name)
+	}
+	graph.tasks[name] = &task{
+		name:      name,
+		worker:    worker,
+		dependsOn: dependsOn,
+	}
+	graph.names = append(graph.names, name)
+	return nil
+}
+
+// Validate checks that every dependency is declared and that there are no dependency cycles.
+func (graph *TaskGraph) Validate() error {
+	for _, name := range graph.names {
+		for _, dependency := range graph.tasks[name].dependsOn {
+			if _, exists := graph.tasks[dependency]; !exists {
+				return fmt.Errorf("task '%s' depends on undeclared task '%s'", name, dependency)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			// report only the part of the path that forms the cycle
+			for idx, entry := range path {
+				if entry == name {
+					return fmt.Errorf("dependency cycle: %s", strings.Join(append(path[idx:], name), " -> "))
+				}
+			}
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+		for _, dependency := range graph.tasks[name].dependsOn {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, name := range graph.names {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run validates the graph, then runs every task and blocks until all tasks have finished or been skipped. The
+// results are in the order the tasks were declared; the returned error is a *WorkError when any task did not
+// succeed. Nothing is run if the graph is invalid.
+func (graph *TaskGraph) Run(ctx context.Context) ([]TaskResult, error) {
+	if err := graph.Validate(); err != nil {
+		return nil, err
+	}
+
+	fr, err := newWorkFrame()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var interrupts *interruptHandler
+	if graph.HandleInterrupt {
+		interrupts = handleInterrupts(fr, cancel)
+	}
+
+	results, err := graph.run(ctx, fr)
+
+	if interrupts != nil {
+		interrupts.stop(func() string {
+			var unfinished []string
+			for _, result := range results {
+				if result.Cancelled {
+					unfinished = append(unfinished, result.Name)
+				}
+			}
+			return interruptReport(unfinished, len(results), "tasks")
+		})
+	}
+
+	fr.Close()
+
+	ansi.CursorShow()
+
+	return results, err
+}
+
+func (graph *TaskGraph) run(ctx context.Context, lines lineSource) ([]TaskResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	maxConcurrent := int(graph.maxConcurrent)
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+
+	results := make([]TaskResult, len(graph.names))
+	var ready []*task
+	for idx, name := range graph.names {
+		current := graph.tasks[name]
+		current.dependents = nil
+		current.waiting = len(current.dependsOn)
+		current.line = nil
+		current.result = &results[idx]
+		current.result.Name = name
+	}
+	for _, name := range graph.names {
+		current := graph.tasks[name]
+		for _, dependency := range current.dependsOn {
+			graph.tasks[dependency].dependents = append(graph.tasks[dependency].dependents, current)
+		}
+	}
+
+	skip := func(current *task, reason error) {
+		current.result.Skipped = true
+		current.result.Err = reason
+		current.result.Cancelled = ctx.Err() != nil
+		line := current.line
+		if line == nil {
+			var err error
+			if line, err = lines.Append(); err != nil {
+				return
+			}
+		}
+		line.WriteString(fmt.Sprintf("%s: skipped (%v)", current.name, reason))
+		lines.Remove(line)
+	}
+
+	// skipDependents skips every task that (transitively) depends on the given task
+	var skipDependents func(current *task, reason error)
+	skipDependents = func(current *task, reason error) {
+		for _, dependent := range current.dependents {
+			if dependent.result.Skipped {
+				continue
+			}
+			skip(dependent, reason)
+			skipDependents(dependent, reason)
+		}
+	}
+
+	// ready tasks get a line straight away so that everything that could run is visible
+	markReady := func(current *task) {
+		line, err := lines.Append()
+		if err != nil {
+			current.result.Err = err
+			skipDependents(current, fmt.Errorf("dependency '%s' failed", current.name))
+			return
+		}
+		line.WriteString(fmt.Sprintf("%s: waiting", current.name))
+		current.line = line
+		ready = append(ready, current)
+	}
+	for _, name := range graph.names {
+		if current := graph.tasks[name]; current.waiting == 0 {
+			markReady(current)
+		}
+	}
+
+	done := make(chan taskDone)
+	running := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			for _, current := range ready {
+				skip(current, err)
+				skipDependents(current, err)
+			}
+			ready = nil
+		}
+
+		for len(ready) > 0 && running < maxConcurrent {
+			current := ready[0]
+			ready = ready[1:]
+			running++
+			go func(current *task) {
+				err := current.worker.Work(ctx, current.line)
+				done <- taskDone{task: current, err: err}
+			}(current)
+		}
+
+		// anything not yet finished depends on a task that was skipped
+		if running == 0 {
+			break
+		}
+
+		event := <-done
+		running--
+
+		current := event.task
+		current.result.Err = event.err
+		current.result.Cancelled = ctx.Err() != nil
+		lines.Remove(current.line)
+
+		if event.err != nil {
+			skipDependents(current, fmt.Errorf("dependency '%s' failed", current.name))
+			if graph.FailFast {
+				cancel()
+			}
+			continue
+		}
+
+		for _, dependent := range current.dependents {
+			dependent.waiting--
+			if dependent.waiting == 0 && !dependent.result.Skipped {
+				markReady(dependent)
+			}
+		}
+	}
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", result.Name, result.Err))
+		}
+	}
+	if len(errs) > 0 {
+		return results, &WorkError{Errors: errs, Total: len(results)}
+	}
+	return results, nil
+}
@@ -0,0 +1,141 @@
+package recipe
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+func noopTask(context.Context, *frame.Line) error {
+	return nil
+}
+
+func Test_TaskGraph_Validate(t *testing.T) {
+	tables := []struct {
+		name     string
+		tasks    map[string][]string
+		order    []string
+		expected string
+	}{
+		{
+			name:  "valid",
+			tasks: map[string][]string{"fetch": nil, "compile": {"fetch"}, "test": {"compile", "fetch"}},
+			order: []string{"test", "compile", "fetch"},
+		},
+		{
+			name:     "undeclared",
+			tasks:    map[string][]string{"compile": {"fetch"}},
+			order:    []string{"compile"},
+			expected: "task 'compile' depends on undeclared task 'fetch'",
+		},
+		{
+			name:     "cycle",
+			tasks:    map[string][]string{"fetch": nil, "a": {"fetch", "b"}, "b": {"c"}, "c": {"a"}},
+			order:    []string{"fetch", "a", "b", "c"},
+			expected: "dependency cycle: a -> b -> c -> a",
+		},
+		{
+			name:     "self",
+			tasks:    map[string][]string{"a": {"a"}},
+			order:    []string{"a"},
+			expected: "dependency cycle: a -> a",
+		},
+	}
+
+	for _, table := range tables {
+		graph := NewTaskGraph(1)
+		for _, name := range table.order {
+			graph.AddTask(name, WorkerFunc(noopTask), table.tasks[name]...)
+		}
+
+		err := graph.Validate()
+		if table.expected == "" && err != nil {
+			t.Errorf("%s: expected no error, got %v", table.name, err)
+		} else if table.expected != "" && (err == nil || err.Error() != table.expected) {
+			t.Errorf("%s: expected error '%s', got %v", table.name, table.expected, err)
+		}
+	}
+
+	graph := NewTaskGraph(1)
+	graph.AddTask("a", WorkerFunc(noopTask))
+	if err := graph.AddTask("a", WorkerFunc(noopTask)); err == nil {
+		t.Errorf("TaskGraph.AddTask(): expected an error for a duplicate task")
+	}
+}
+
+func Test_TaskGraph_run(t *testing.T) {
+	graph := NewTaskGraph(2)
+
+	var lock sync.Mutex
+	var ran []string
+	task := func(name string, err error) Worker {
+		return WorkerFunc(func(context.Context, *frame.Line) error {
+			lock.Lock()
+			ran = append(ran, name)
+			lock.Unlock()
+			return err
+		})
+	}
+
+	graph.AddTask("package", task("package", nil), "compile", "lint")
+	graph.AddTask("compile", task("compile", fmt.Errorf("boom")), "fetch")
+	graph.AddTask("fetch", task("fetch", nil))
+	graph.AddTask("lint", task("lint", nil), "fetch")
+	graph.AddTask("publish", task("publish", nil), "package")
+
+	lines := newTestLines()
+	results, err := graph.run(context.Background(), lines)
+
+	if _, ok := err.(*WorkError); !ok {
+		t.Fatalf("TaskGraph.run(): expected a WorkError, got %v", err)
+	}
+
+	if len(ran) != 3 || ran[0] != "fetch" {
+		t.Errorf("TaskGraph.run(): expected fetch to run first followed by compile and lint, got %v", ran)
+	}
+
+	expected := map[string]string{
+		"package": "skipped",
+		"compile": "failed",
+		"fetch":   "succeeded",
+		"lint":    "succeeded",
+		"publish": "skipped",
+	}
+	for _, result := range results {
+		status := "succeeded"
+		switch {
+		case result.Skipped:
+			status = "skipped"
+		case result.Err != nil:
+			status = "failed"
+		}
+		if status != expected[result.Name] {
+			t.Errorf("TaskGraph.run(): expected %s to be %s, got %s (%v)", result.Name, expected[result.Name], status, result.Err)
+		}
+	}
+
+	if lines.active != 0 {
+		t.Errorf("TaskGraph.run(): expected all lines to be removed, %d remain", lines.active)
+	}
+}
+
+func Test_TaskGraph_FailFast(t *testing.T) {
+	graph := NewTaskGraph(1)
+	graph.FailFast = true
+
+	graph.AddTask("a", WorkerFunc(func(context.Context, *frame.Line) error {
+		return fmt.Errorf("boom")
+	}))
+	graph.AddTask("b", WorkerFunc(func(context.Context, *frame.Line) error {
+		t.Errorf("TaskGraph.run(): expected b not to run")
+		return nil
+	}))
+
+	results, _ := graph.run(context.Background(), newTestLines())
+	if !results[1].Skipped || results[1].Err != context.Canceled {
+		t.Errorf("TaskGraph.run(): expected b to be skipped, got %+v", results[1])
+	}
+}
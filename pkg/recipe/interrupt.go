@@ -0,0 +1,125 @@
+package recipe
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+
+	"github.com/k0kubun/go-ansi"
+)
+
+const (
+	interruptMessage = "cancelling… (press Ctrl-C again to force)"
+	// interruptExitCode is the conventional exit code of a process killed by SIGINT
+	interruptExitCode = 130
+	// maxReportedNames limits how many unfinished items are named in the interrupt report
+	maxReportedNames = 5
+)
+
+// forceExit restores the terminal and terminates the process on a second interrupt (replaced in tests)
+var forceExit = func() {
+	frame.Close()
+	ansi.CursorShow()
+	os.Exit(interruptExitCode)
+}
+
+// footerSource is the part of a frame.Frame that interrupt messages are drawn on
+type footerSource interface {
+	AppendFooter() (*frame.Line, error)
+}
+
+// interruptHandler cancels running work on the first interrupt (showing a message in a footer while the work
+// drains) and restores the terminal then exits on the second interrupt.
+type interruptHandler struct {
+	footers     footerSource
+	cancel      func()
+	signals     chan os.Signal
+	done        chan struct{}
+	watching    chan struct{}
+	line        *frame.Line
+	interrupted bool
+	lock        sync.Mutex
+}
+
+func handleInterrupts(footers footerSource, cancel func()) *interruptHandler {
+	handler := &interruptHandler{
+		footers:  footers,
+		cancel:   cancel,
+		signals:  make(chan os.Signal, 1),
+		done:     make(chan struct{}),
+		watching: make(chan struct{}),
+	}
+	signal.Notify(handler.signals, os.Interrupt)
+
+	go handler.watch()
+
+	return handler
+}
+
+func (handler *interruptHandler) watch() {
+	defer close(handler.watching)
+	for {
+		select {
+		case <-handler.done:
+			return
+		case <-handler.signals:
+			handler.interrupt()
+		}
+	}
+}
+
+func (handler *interruptHandler) interrupt() {
+	handler.lock.Lock()
+	if handler.interrupted {
+		handler.lock.Unlock()
+		forceExit()
+		return
+	}
+	handler.interrupted = true
+	handler.lock.Unlock()
+
+	handler.cancel()
+
+	line, err := handler.footers.AppendFooter()
+	if err != nil {
+		return
+	}
+	line.WriteString(interruptMessage)
+
+	handler.lock.Lock()
+	handler.line = line
+	handler.lock.Unlock()
+}
+
+// stop restores the default interrupt behavior. If the work was interrupted, the footer message is replaced with
+// the given report.
+func (handler *interruptHandler) stop(report func() string) {
+	signal.Stop(handler.signals)
+	close(handler.done)
+	// an interrupt that is still being handled would otherwise draw its message over the report
+	<-handler.watching
+
+	handler.lock.Lock()
+	defer handler.lock.Unlock()
+
+	if handler.line != nil {
+		handler.line.WriteString(report())
+	}
+}
+
+// interruptReport summarizes which of the given number of items (e.g. "tasks") did not finish once interrupted.
+func interruptReport(unfinished []string, total int, items string) string {
+	if len(unfinished) == 0 {
+		return fmt.Sprintf("interrupted: all %s finished", items)
+	}
+
+	names := unfinished
+	if len(names) > maxReportedNames {
+		names = append(names[:maxReportedNames:maxReportedNames], "…")
+	}
+	return fmt.Sprintf("interrupted: %d of %d %s did not finish (%s)", len(unfinished), total, items, strings.Join(names, ", "))
+}
@@ -0,0 +1,214 @@
+package recipe
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+// testFooters records the footer lines handed out to an interrupt handler
+type testFooters struct {
+	*testLines
+	footers []*frame.Line
+}
+
+func (footers *testFooters) AppendFooter() (*frame.Line, error) {
+	line, err := footers.Append()
+	footers.footers = append(footers.footers, line)
+	return line, err
+}
+
+func Test_interruptHandler(t *testing.T) {
+	forced := 0
+	original := forceExit
+	forceExit = func() {
+		forced++
+	}
+	defer func() {
+		forceExit = original
+	}()
+
+	footers := &testFooters{testLines: newTestLines()}
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := handleInterrupts(footers, cancel)
+
+	handler.interrupt()
+
+	if ctx.Err() == nil {
+		t.Errorf("interruptHandler.interrupt(): expected the context to be cancelled")
+	}
+	if len(footers.footers) != 1 || forced != 0 {
+		t.Fatalf("interruptHandler.interrupt(): expected a footer message and no exit")
+	}
+
+	writes := 0
+	footers.footers[0].OnWrite(func(*frame.Line) {
+		writes++
+	})
+
+	handler.interrupt()
+	if forced != 1 {
+		t.Errorf("interruptHandler.interrupt(): expected a second interrupt to force an exit")
+	}
+
+	handler.stop(func() string {
+		return "interrupted: 2 of 5 work items did not finish"
+	})
+
+	if writes != 1 {
+		t.Errorf("interruptHandler.stop(): expected the report to replace the footer message")
+	}
+}
+
+func Test_interruptHandler_signal(t *testing.T) {
+	footers := &testFooters{testLines: newTestLines()}
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := handleInterrupts(footers, cancel)
+	defer handler.stop(func() string { return "" })
+
+	process, _ := os.FindProcess(os.Getpid())
+	if err := process.Signal(syscall.SIGINT); err != nil {
+		t.Skipf("unable to signal the test process: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Errorf("interruptHandler: expected SIGINT to cancel the context")
+	}
+}
+
+// blockingFooters hands out footer lines only once released
+type blockingFooters struct {
+	*testFooters
+	requested chan struct{}
+	release   chan struct{}
+}
+
+func (footers *blockingFooters) AppendFooter() (*frame.Line, error) {
+	close(footers.requested)
+	<-footers.release
+	return footers.testFooters.AppendFooter()
+}
+
+func Test_interruptHandler_stopWhileInterrupting(t *testing.T) {
+	footers := &blockingFooters{
+		testFooters: &testFooters{testLines: newTestLines()},
+		requested:   make(chan struct{}),
+		release:     make(chan struct{}),
+	}
+	handler := handleInterrupts(footers, func() {})
+	defer signal.Stop(handler.signals)
+
+	handler.signals <- os.Interrupt
+	<-footers.requested
+
+	stopped := make(chan struct{})
+	go func() {
+		handler.stop(func() string { return "interrupted: all tasks finished" })
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatalf("interruptHandler.stop(): expected to wait for the interrupt being handled")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(footers.release)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("interruptHandler.stop(): expected to return once the interrupt was handled")
+	}
+
+	if actual := footers.footers[0].Contents(); actual != "interrupted: all tasks finished" {
+		t.Errorf("interruptHandler.stop(): expected the report to replace the footer message, got '%s'", actual)
+	}
+}
+
+// namedWorker is work that is named in reports
+type namedWorker struct {
+	name string
+	work WorkerFunc
+}
+
+func (worker namedWorker) Work(ctx context.Context, line *frame.Line) error {
+	return worker.work(ctx, line)
+}
+
+func (worker namedWorker) String() string {
+	return worker.name
+}
+
+func Test_WorkQueue_interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	wq := NewWorkQueue(1)
+	wq.AddWork(namedWorker{name: "finished", work: noopTask})
+	// a worker may give up on cancellation with an error of its own
+	wq.AddWork(namedWorker{name: "stopped", work: func(context.Context, *frame.Line) error {
+		cancel()
+		return fmt.Errorf("gave up")
+	}})
+	wq.AddWork(WorkerFunc(noopTask))
+
+	results, _ := wq.run(ctx, newTestLines(), nil)
+
+	var unfinished []string
+	for idx, result := range results {
+		if result.Cancelled {
+			unfinished = append(unfinished, workName(result.Worker, idx))
+		}
+	}
+
+	expected := "interrupted: 2 of 3 work items did not finish (stopped, #3)"
+	if actual := interruptReport(unfinished, len(results), "work items"); actual != expected {
+		t.Errorf("WorkQueue.run(): expected report '%s', got '%s'", expected, actual)
+	}
+}
+
+func Test_TaskGraph_interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	graph := NewTaskGraph(1)
+	graph.AddTask("fetch", WorkerFunc(noopTask))
+	graph.AddTask("build", WorkerFunc(func(context.Context, *frame.Line) error {
+		cancel()
+		return fmt.Errorf("gave up")
+	}), "fetch")
+	graph.AddTask("test", WorkerFunc(noopTask), "build")
+
+	results, _ := graph.run(ctx, newTestLines())
+
+	expected := []bool{false, true, true}
+	for idx, result := range results {
+		if result.Cancelled != expected[idx] {
+			t.Errorf("TaskGraph.run(): expected task '%s' cancelled=%v", result.Name, expected[idx])
+		}
+	}
+}
+
+func Test_interruptReport(t *testing.T) {
+	tables := []struct {
+		unfinished []string
+		total      int
+		expected   string
+	}{
+		{nil, 3, "interrupted: all tasks finished"},
+		{[]string{"build"}, 3, "interrupted: 1 of 3 tasks did not finish (build)"},
+		{[]string{"a", "b", "c", "d", "e", "f", "g"}, 9, "interrupted: 7 of 9 tasks did not finish (a, b, c, d, e, …)"},
+	}
+
+	for _, table := range tables {
+		if actual := interruptReport(table.unfinished, table.total, "tasks"); actual != table.expected {
+			t.Errorf("interruptReport(%v): expected '%s', got '%s'", table.unfinished, table.expected, actual)
+		}
+	}
+}
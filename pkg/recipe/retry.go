@@ -0,0 +1,141 @@
+package recipe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/component"
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+// RetryPolicy describes how failed work is retried (see WithRetry).
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt (one second when zero)
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts (no cap when zero)
+	MaxBackoff time.Duration
+	// Multiplier grows the wait after each attempt (two when zero)
+	Multiplier float64
+	// Jitter randomizes each wait by up to the given fraction in either direction (e.g. 0.2 for ±20%)
+	Jitter float64
+	// Retryable decides which errors are worth retrying (all errors except context cancellation when nil)
+	Retryable func(err error) bool
+}
+
+// retryWorker runs a worker until it succeeds or the retry policy gives up
+type retryWorker struct {
+	worker Worker
+	policy RetryPolicy
+}
+
+// WithRetry wraps a worker so that it is retried according to the given policy. Between attempts the line shows
+// the attempt number and a countdown to the next attempt, and the trail entry records the attempt count when
+// more than one attempt was needed.
+func WithRetry(worker Worker, policy RetryPolicy) Worker {
+	return &retryWorker{
+		worker: worker,
+		policy: policy,
+	}
+}
+
+func (retry *retryWorker) Work(ctx context.Context, line *frame.Line) error {
+	maxAttempts := retry.policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	// the countdown replaces the trail formatter of the line, which must be put back however the work ends
+	formatter := line.TrailFormatter()
+	attempt, retried := 1, false
+	defer func() {
+		if !retried {
+			line.SetTrailFormatter(formatter)
+			return
+		}
+		attempts := fmt.Sprintf(" (attempt %d/%d)", attempt, maxAttempts)
+		line.SetTrailFormatter(func(entry frame.TrailEntry) (string, bool) {
+			if formatter != nil {
+				content, keep := formatter(entry)
+				if !keep {
+					return "", false
+				}
+				entry.Content = content
+			}
+			return entry.Content + attempts, true
+		})
+	}()
+
+	// a single countdown is restarted for every wait, since each one would add hooks to the line for good
+	var countdown *component.Countdown
+	var err error
+	for ; ; attempt++ {
+		err = retry.worker.Work(ctx, line)
+		if err == nil || attempt >= maxAttempts || !retry.policy.retryable(err) {
+			return err
+		}
+
+		retried = true
+		contents := line.Contents()
+		wait := retry.policy.backoff(attempt, rand.Float64())
+		message := fmt.Sprintf("attempt %d/%d, retrying in %%s", attempt+1, maxAttempts)
+		if countdown == nil {
+			countdown = component.NewCountdown(line, message, time.Now().Add(wait))
+		} else {
+			countdown.SetMessage(message)
+			countdown.Restart(time.Now().Add(wait))
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			countdown.Stop()
+			// the line should show the outcome of the last attempt, not a retry that will never happen
+			line.WriteString(contents)
+			return ctx.Err()
+		case <-timer.C:
+			countdown.Stop()
+		}
+	}
+}
+
+func (policy RetryPolicy) retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if policy.Retryable == nil {
+		return true
+	}
+	return policy.Retryable(err)
+}
+
+// backoff returns the wait after the given (failed) attempt, where random is in [0, 1) and spreads the jitter.
+func (policy RetryPolicy) backoff(attempt int, random float64) time.Duration {
+	initial := policy.InitialBackoff
+	if initial <= 0 {
+		initial = time.Second
+	}
+	multiplier := policy.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	wait := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	if policy.MaxBackoff > 0 && wait > float64(policy.MaxBackoff) {
+		wait = float64(policy.MaxBackoff)
+	}
+
+	if policy.Jitter > 0 {
+		wait *= 1 + policy.Jitter*(2*random-1)
+	}
+	if policy.MaxBackoff > 0 && wait > float64(policy.MaxBackoff) {
+		wait = float64(policy.MaxBackoff)
+	}
+	return time.Duration(wait)
+}
@@ -0,0 +1,124 @@
+package recipe
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+func Test_RetryPolicy_backoff(t *testing.T) {
+	tables := []struct {
+		name     string
+		policy   RetryPolicy
+		attempt  int
+		random   float64
+		expected time.Duration
+	}{
+		{"defaults", RetryPolicy{}, 1, 0.5, time.Second},
+		{"exponential", RetryPolicy{InitialBackoff: time.Second}, 3, 0.5, 4 * time.Second},
+		{"multiplier", RetryPolicy{InitialBackoff: time.Second, Multiplier: 3}, 3, 0.5, 9 * time.Second},
+		{"capped", RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}, 6, 0.5, 5 * time.Second},
+		{"jitter low", RetryPolicy{InitialBackoff: time.Second, Jitter: 0.5}, 1, 0, 500 * time.Millisecond},
+		{"jitter high", RetryPolicy{InitialBackoff: time.Second, Jitter: 0.5}, 2, 1, 3 * time.Second},
+		{"jitter capped", RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 2 * time.Second, Jitter: 0.5}, 2, 1, 2 * time.Second},
+	}
+
+	for _, table := range tables {
+		if actual := table.policy.backoff(table.attempt, table.random); actual != table.expected {
+			t.Errorf("%s: expected backoff %v, got %v", table.name, table.expected, actual)
+		}
+	}
+}
+
+// trailOf renders the trail entry of a line as a frame would (without a frame level formatter).
+func trailOf(line *frame.Line) string {
+	entry := frame.TrailEntry{Content: line.Contents()}
+	formatter := line.TrailFormatter()
+	if formatter == nil {
+		return entry.Content
+	}
+	message, _ := formatter(entry)
+	return message
+}
+
+func Test_WithRetry(t *testing.T) {
+	permanent := fmt.Errorf("permanent")
+	tables := []struct {
+		name     string
+		failures []error
+		attempts int
+		err      string
+		trail    string
+	}{
+		{"first try", nil, 1, "", "done"},
+		{"eventually", []error{fmt.Errorf("flaky"), fmt.Errorf("flaky")}, 3, "", "done (attempt 3/3)"},
+		{"exhausted", []error{fmt.Errorf("a"), fmt.Errorf("b"), fmt.Errorf("c"), fmt.Errorf("d")}, 3, "c", "failed: c (attempt 3/3)"},
+		{"not retryable", []error{permanent, fmt.Errorf("flaky")}, 1, "permanent", "failed: permanent"},
+	}
+
+	for _, table := range tables {
+		attempts := 0
+		worker := WithRetry(WorkerFunc(func(_ context.Context, line *frame.Line) error {
+			attempts++
+			if attempts <= len(table.failures) {
+				err := table.failures[attempts-1]
+				line.WriteString("failed: " + err.Error())
+				return err
+			}
+			line.WriteString("done")
+			return nil
+		}), RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			Retryable: func(err error) bool {
+				return err != permanent
+			},
+		})
+
+		lines := newTestLines()
+		line, _ := lines.Append()
+		err := worker.Work(context.Background(), line)
+
+		if attempts != table.attempts {
+			t.Errorf("%s: expected %d attempts, got %d", table.name, table.attempts, attempts)
+		}
+		if (err == nil && table.err != "") || (err != nil && err.Error() != table.err) {
+			t.Errorf("%s: expected error '%s', got %v", table.name, table.err, err)
+		}
+		if actual := trailOf(line); actual != table.trail {
+			t.Errorf("%s: expected trail '%s', got '%s'", table.name, table.trail, actual)
+		}
+	}
+}
+
+func Test_WithRetry_cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	worker := WithRetry(WorkerFunc(func(_ context.Context, line *frame.Line) error {
+		attempts++
+		line.WriteString("failed: flaky")
+		cancel()
+		return fmt.Errorf("flaky")
+	}), RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour})
+
+	line, _ := newTestLines().Append()
+	line.SetTrailFormatter(func(entry frame.TrailEntry) (string, bool) {
+		return "✘ " + entry.Content, true
+	})
+
+	if err := worker.Work(ctx, line); err != context.Canceled || attempts != 1 {
+		t.Errorf("WithRetry(): expected cancellation to stop retries, got %v after %d attempts", err, attempts)
+	}
+
+	// the pending retry is dropped: the line shows the last attempt and keeps its own formatter
+	if actual := line.Contents(); actual != "failed: flaky" {
+		t.Errorf("WithRetry(): expected the line to show the last attempt, got '%s'", actual)
+	}
+	if actual := trailOf(line); actual != "✘ failed: flaky (attempt 1/5)" {
+		t.Errorf("WithRetry(): unexpected trail '%s'", actual)
+	}
+}
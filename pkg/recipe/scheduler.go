@@ -0,0 +1,218 @@
+package recipe
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// queuedWork is a single item of work waiting in (or taken from) a WorkQueue
+type queuedWork struct {
+	index    int
+	priority int
+	worker   Worker
+}
+
+// workHeap orders queued work by priority (highest first), then by the order it was added
+type workHeap []*queuedWork
+
+func (h workHeap) Len() int {
+	return len(h)
+}
+
+func (h workHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].index < h[j].index
+}
+
+func (h workHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *workHeap) Push(item interface{}) {
+	*h = append(*h, item.(*queuedWork))
+}
+
+func (h *workHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// workList is the pending work of a single queue (a WorkQueue or a ChildQueue) along with how much of it is running
+type workList struct {
+	pending workHeap
+	running int
+	// closed is set once nothing takes work from the list anymore (see next)
+	closed bool
+}
+
+// scheduler hands out queued work while honoring a concurrency limit and a minimum interval between starts, both
+// of which may change while work is running. The limit is shared by a queue and all of its child queues.
+type scheduler struct {
+	lock      sync.Mutex
+	cond      *sync.Cond
+	running   int
+	limit     int
+	interval  time.Duration
+	lastStart time.Time
+	// borrowers is the number of child queues waiting on a free slot (which take precedence over top-level work)
+	borrowers int
+}
+
+func newScheduler(limit int, rate float64) *scheduler {
+	sched := &scheduler{}
+	sched.cond = sync.NewCond(&sched.lock)
+	sched.setLimit(limit)
+	sched.setRate(rate)
+	return sched
+}
+
+// push adds work to the given list, failing once the list no longer hands out work.
+func (sched *scheduler) push(list *workList, item *queuedWork) error {
+	sched.lock.Lock()
+	if list.closed {
+		sched.lock.Unlock()
+		return fmt.Errorf("queue is no longer accepting work")
+	}
+	heap.Push(&list.pending, item)
+	sched.lock.Unlock()
+	sched.cond.Broadcast()
+	return nil
+}
+
+func (sched *scheduler) setLimit(limit int) {
+	if limit < 1 {
+		limit = 1
+	}
+	sched.lock.Lock()
+	sched.limit = limit
+	sched.lock.Unlock()
+	sched.cond.Broadcast()
+}
+
+// setRate limits how many items may start per second (zero or less removes the limit).
+func (sched *scheduler) setRate(rate float64) {
+	var interval time.Duration
+	if rate > 0 {
+		interval = time.Duration(float64(time.Second) / rate)
+	}
+	sched.lock.Lock()
+	sched.interval = interval
+	sched.lock.Unlock()
+	sched.cond.Broadcast()
+}
+
+// next blocks until an item of work from the given list may start and marks it as running. It returns false once
+// the list has no pending or running work left, or when the context is cancelled (leaving the remaining work
+// pending, see drain). From then on the list is closed to new work. Work from child queues starts ahead of top-level
+// work so that running work can finish.
+func (sched *scheduler) next(ctx context.Context, list *workList, child bool) (*queuedWork, bool) {
+	sched.lock.Lock()
+	defer sched.lock.Unlock()
+
+	for {
+		if ctx.Err() != nil {
+			list.closed = true
+			return nil, false
+		}
+
+		if len(list.pending) == 0 {
+			// running work may still add more work to the queue
+			if list.running == 0 {
+				list.closed = true
+				return nil, false
+			}
+			sched.cond.Wait()
+			continue
+		}
+
+		if !child && sched.borrowers > 0 {
+			sched.cond.Wait()
+			continue
+		}
+
+		if sched.running >= sched.limit {
+			if child {
+				sched.borrowers++
+				sched.cond.Wait()
+				sched.borrowers--
+			} else {
+				sched.cond.Wait()
+			}
+			continue
+		}
+
+		if wait := sched.interval - time.Since(sched.lastStart); sched.interval > 0 && wait > 0 {
+			sched.lock.Unlock()
+			select {
+			case <-ctx.Done():
+			case <-time.After(wait):
+			}
+			sched.lock.Lock()
+			continue
+		}
+
+		sched.running++
+		list.running++
+		sched.lastStart = time.Now()
+		return heap.Pop(&list.pending).(*queuedWork), true
+	}
+}
+
+// done marks an item of work from the given list as finished, making room for the next.
+func (sched *scheduler) done(list *workList) {
+	sched.lock.Lock()
+	sched.running--
+	list.running--
+	sched.lock.Unlock()
+	sched.cond.Broadcast()
+}
+
+// lend gives up the slot of running work while it waits on a child queue.
+func (sched *scheduler) lend() {
+	sched.lock.Lock()
+	sched.running--
+	sched.lock.Unlock()
+	sched.cond.Broadcast()
+}
+
+// reclaim takes back a slot given up with lend (ahead of any top-level work). This does not give up on cancellation
+// since the slot is released again once the running work returns.
+func (sched *scheduler) reclaim() {
+	sched.lock.Lock()
+	defer sched.lock.Unlock()
+
+	for sched.running >= sched.limit {
+		sched.borrowers++
+		sched.cond.Wait()
+		sched.borrowers--
+	}
+	sched.running++
+}
+
+// wake unblocks next once the context is cancelled (until stop is closed).
+func (sched *scheduler) wake(ctx context.Context, stop chan struct{}) {
+	select {
+	case <-ctx.Done():
+		sched.lock.Lock()
+		sched.lock.Unlock()
+		sched.cond.Broadcast()
+	case <-stop:
+	}
+}
+
+// drain removes and returns all pending work from the given list.
+func (sched *scheduler) drain(list *workList) []*queuedWork {
+	sched.lock.Lock()
+	defer sched.lock.Unlock()
+
+	items := []*queuedWork(list.pending)
+	list.pending = nil
+	return items
+}
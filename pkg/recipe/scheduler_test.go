@@ -0,0 +1,192 @@
+package recipe
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+func Test_WorkQueue_priority(t *testing.T) {
+	wq := NewWorkQueue(1)
+
+	var lock sync.Mutex
+	var order []string
+	record := func(name string) Worker {
+		return WorkerFunc(func(context.Context, *frame.Line) error {
+			lock.Lock()
+			order = append(order, name)
+			lock.Unlock()
+			return nil
+		})
+	}
+
+	wq.AddWork(record("low-1"))
+	wq.AddWorkWithPriority(record("high"), 10)
+	wq.AddWork(record("low-2"))
+	wq.AddWorkWithPriority(record("background"), -1)
+	wq.AddWorkWithPriority(WorkerFunc(func(ctx context.Context, line *frame.Line) error {
+		// work added while running still jumps the queue
+		wq.AddWorkWithPriority(record("urgent"), 20)
+		return record("first").Work(ctx, line)
+	}), 100)
+
+	results, err := wq.run(context.Background(), newTestLines(), nil)
+	if err != nil {
+		t.Fatalf("WorkQueue.run(): expected no error, got %v", err)
+	}
+
+	expected := []string{"first", "urgent", "high", "low-1", "low-2", "background"}
+	if len(order) != len(expected) {
+		t.Fatalf("WorkQueue.run(): expected order %v, got %v", expected, order)
+	}
+	for idx := range expected {
+		if order[idx] != expected[idx] {
+			t.Errorf("WorkQueue.run(): expected order %v, got %v", expected, order)
+			break
+		}
+	}
+
+	if len(results) != 6 {
+		t.Errorf("WorkQueue.run(): expected a result for work added while running, got %d results", len(results))
+	}
+}
+
+func Test_WorkQueue_SetConcurrency(t *testing.T) {
+	wq := NewWorkQueue(1)
+
+	var running, peak int64
+	release := make(chan struct{})
+	for idx := 0; idx < 4; idx++ {
+		wq.AddWork(WorkerFunc(func(context.Context, *frame.Line) error {
+			current := atomic.AddInt64(&running, 1)
+			defer atomic.AddInt64(&running, -1)
+			for {
+				observed := atomic.LoadInt64(&peak)
+				if current <= observed || atomic.CompareAndSwapInt64(&peak, observed, current) {
+					break
+				}
+			}
+			<-release
+			return nil
+		}))
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		wq.SetConcurrency(4)
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+
+	wq.run(context.Background(), newTestLines(), nil)
+
+	if peak != 4 {
+		t.Errorf("WorkQueue.SetConcurrency(): expected 4 concurrent workers after raising the limit, got %d", peak)
+	}
+}
+
+func Test_WorkQueue_SetRateLimit(t *testing.T) {
+	wq := NewWorkQueue(4)
+	wq.SetRateLimit(20)
+
+	var lock sync.Mutex
+	var starts []time.Time
+	for idx := 0; idx < 4; idx++ {
+		wq.AddWork(WorkerFunc(func(context.Context, *frame.Line) error {
+			lock.Lock()
+			starts = append(starts, time.Now())
+			lock.Unlock()
+			return nil
+		}))
+	}
+
+	wq.run(context.Background(), newTestLines(), nil)
+
+	// 20 per second allows a start every 50ms
+	if elapsed := starts[len(starts)-1].Sub(starts[0]); elapsed < 140*time.Millisecond {
+		t.Errorf("WorkQueue.SetRateLimit(): expected starts to be spread over at least 150ms, got %v", elapsed)
+	}
+}
+
+// flagWorker records whether it was run
+type flagWorker struct {
+	ran int32
+}
+
+func (worker *flagWorker) Work(context.Context, *frame.Line) error {
+	atomic.StoreInt32(&worker.ran, 1)
+	return nil
+}
+
+func Test_WorkQueue_addWhileFinishing(t *testing.T) {
+	for iteration := 0; iteration < 50; iteration++ {
+		wq := NewWorkQueue(2)
+		wq.AddWork(&flagWorker{})
+
+		// keep adding work from outside the queue while it runs out of work and finishes
+		finished := make(chan struct{})
+		adding := make(chan struct{})
+		go func() {
+			defer close(adding)
+			for idx := 0; idx < 200; idx++ {
+				select {
+				case <-finished:
+					return
+				default:
+				}
+				if wq.AddWork(&flagWorker{}) != nil {
+					return
+				}
+			}
+		}()
+
+		results, err := wq.run(context.Background(), newTestLines(), nil)
+		close(finished)
+		<-adding
+
+		if err != nil {
+			t.Fatalf("WorkQueue.run(): expected no error, got %v", err)
+		}
+		for _, result := range results {
+			if atomic.LoadInt32(&result.Worker.(*flagWorker).ran) == 0 {
+				t.Fatalf("WorkQueue.run(): work that never ran was reported as succeeded")
+			}
+		}
+	}
+}
+
+func Test_scheduler_closed(t *testing.T) {
+	sched := newScheduler(1, 0)
+	list := &workList{}
+
+	if err := sched.push(list, &queuedWork{index: 0}); err != nil {
+		t.Fatalf("scheduler.push(): expected no error, got %v", err)
+	}
+	if _, ok := sched.next(context.Background(), list, false); !ok {
+		t.Fatalf("scheduler.next(): expected pending work")
+	}
+
+	// running work may still add to the list
+	if err := sched.push(list, &queuedWork{index: 1}); err != nil {
+		t.Fatalf("scheduler.push(): expected no error while work is running, got %v", err)
+	}
+	sched.done(list)
+	if _, ok := sched.next(context.Background(), list, false); !ok {
+		t.Fatalf("scheduler.next(): expected work added while running")
+	}
+	sched.done(list)
+
+	if _, ok := sched.next(context.Background(), list, false); ok {
+		t.Fatalf("scheduler.next(): expected the list to be exhausted")
+	}
+	if err := sched.push(list, &queuedWork{index: 2}); err == nil {
+		t.Errorf("scheduler.push(): expected an error once the list is exhausted")
+	}
+	if len(list.pending) != 0 {
+		t.Errorf("scheduler.push(): expected no pending work on an exhausted list")
+	}
+}
@@ -0,0 +1,146 @@
+package recipe
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/component"
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+// SummaryPosition selects where (if anywhere) WorkQueue draws its summary row.
+type SummaryPosition int
+
+const (
+	SummaryNone SummaryPosition = iota
+	SummaryHeader
+	SummaryFooter
+)
+
+// workSummary tracks the progress of all queued work and draws it as a single row.
+type workSummary struct {
+	line      *frame.Line
+	start     time.Time
+	queued    int
+	running   int
+	succeeded int
+	failed    int
+	skipped   int
+	last      string
+	lock      sync.Mutex
+	halt      func()
+}
+
+func newWorkSummary(line *frame.Line) *workSummary {
+	return &workSummary{
+		line:  line,
+		start: time.Now(),
+		halt:  func() {},
+	}
+}
+
+// run refreshes the elapsed time and ETA on the shared clock of the components until stop is called (or the line
+// is closed).
+func (summary *workSummary) run() {
+	summary.draw()
+
+	summary.halt = component.OnTick(func(time.Time) {
+		summary.draw()
+	})
+	summary.line.OnClose(func(*frame.Line) {
+		summary.halt()
+	})
+}
+
+// stop halts the refresh and draws the final counts.
+func (summary *workSummary) stop() {
+	summary.halt()
+	summary.draw()
+}
+
+func (summary *workSummary) started() {
+	summary.update(func() {
+		summary.queued--
+		summary.running++
+	})
+}
+
+func (summary *workSummary) finished(err error) {
+	summary.update(func() {
+		summary.running--
+		if err != nil {
+			summary.failed++
+		} else {
+			summary.succeeded++
+		}
+	})
+}
+
+// queue accounts for work added to the queue.
+func (summary *workSummary) queue() {
+	summary.update(func() {
+		summary.queued++
+	})
+}
+
+// skip accounts for work that was cancelled before it started.
+func (summary *workSummary) skip() {
+	summary.update(func() {
+		summary.queued--
+		summary.skipped++
+	})
+}
+
+func (summary *workSummary) update(fn func()) {
+	if summary == nil {
+		return
+	}
+	summary.lock.Lock()
+	fn()
+	summary.lock.Unlock()
+
+	summary.draw()
+}
+
+func (summary *workSummary) draw() {
+	summary.lock.Lock()
+	contents := summary.render(time.Now())
+	changed := contents != summary.last
+	summary.last = contents
+	summary.lock.Unlock()
+
+	if changed {
+		summary.line.WriteString(contents)
+	}
+}
+
+func (summary *workSummary) render(now time.Time) string {
+	elapsed := now.Sub(summary.start)
+
+	parts := []string{
+		fmt.Sprintf("queued %d", summary.queued),
+		fmt.Sprintf("running %d", summary.running),
+		fmt.Sprintf("succeeded %d", summary.succeeded),
+		fmt.Sprintf("failed %d", summary.failed),
+	}
+	if summary.skipped > 0 {
+		parts = append(parts, fmt.Sprintf("skipped %d", summary.skipped))
+	}
+	parts = append(parts, fmt.Sprintf("elapsed %s", component.FormatDuration(elapsed)))
+
+	// the ETA assumes the remaining work finishes at the same rate as the work that has finished so far
+	remaining := summary.queued + summary.running
+	finished := summary.succeeded + summary.failed
+	switch {
+	case remaining == 0:
+	case finished == 0:
+		parts = append(parts, "ETA --")
+	default:
+		eta := time.Duration(float64(elapsed) / float64(finished) * float64(remaining))
+		parts = append(parts, fmt.Sprintf("ETA %s", component.FormatDuration(eta)))
+	}
+
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,86 @@
+package recipe
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+func Test_workSummary_render(t *testing.T) {
+	start := time.Now()
+
+	tables := []struct {
+		summary  *workSummary
+		elapsed  time.Duration
+		expected string
+	}{
+		{
+			summary:  &workSummary{queued: 4},
+			expected: "queued 4 · running 0 · succeeded 0 · failed 0 · elapsed 0s · ETA --",
+		},
+		{
+			summary:  &workSummary{queued: 2, running: 2, succeeded: 3, failed: 1},
+			elapsed:  20 * time.Second,
+			expected: "queued 2 · running 2 · succeeded 3 · failed 1 · elapsed 20s · ETA 20s",
+		},
+		{
+			summary:  &workSummary{succeeded: 3, failed: 1, skipped: 2},
+			elapsed:  83 * time.Second,
+			expected: "queued 0 · running 0 · succeeded 3 · failed 1 · skipped 2 · elapsed 1m23s",
+		},
+	}
+
+	for _, table := range tables {
+		table.summary.start = start
+		if actual := table.summary.render(start.Add(table.elapsed)); actual != table.expected {
+			t.Errorf("workSummary.render(): expected '%s', got '%s'", table.expected, actual)
+		}
+	}
+}
+
+func Test_WorkQueue_summary(t *testing.T) {
+	lines := newTestLines()
+	summaryLine, _ := lines.Append()
+	summary := newWorkSummary(summaryLine)
+
+	wq := NewWorkQueue(1)
+	wq.FailFast = true
+	wq.AddWork(WorkerFunc(noopTask))
+	wq.AddWork(WorkerFunc(func(ctx context.Context, line *frame.Line) error {
+		return fmt.Errorf("boom")
+	}))
+	wq.AddWork(WorkerFunc(noopTask))
+
+	wq.run(context.Background(), lines, summary)
+
+	if summary.queued != 0 || summary.running != 0 || summary.succeeded != 1 || summary.failed != 1 || summary.skipped != 1 {
+		t.Errorf("WorkQueue.run(): unexpected summary counts %+v", summary)
+	}
+}
+
+func Test_workSummary_run(t *testing.T) {
+	line, _ := newTestLines().Append()
+	summary := newWorkSummary(line)
+
+	summary.run()
+	summary.queue()
+	if actual := line.Contents(); actual != "queued 1 · running 0 · succeeded 0 · failed 0 · elapsed 0s · ETA --" {
+		t.Errorf("workSummary.run(): unexpected row '%s'", actual)
+	}
+
+	halted := false
+	halt := summary.halt
+	summary.halt = func() {
+		halted = true
+		halt()
+	}
+
+	// the refresh stops with the line, like any other component
+	line.Close()
+	if !halted {
+		t.Errorf("workSummary.run(): expected the refresh to stop once the line is closed")
+	}
+}
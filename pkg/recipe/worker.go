@@ -2,21 +2,70 @@ package recipe
 
 import (
 	"context"
+	"fmt"
+	"sync"
 
 	"github.com/wagoodman/jotframe/pkg/frame"
 
 	"github.com/k0kubun/go-ansi"
-	"golang.org/x/sync/semaphore"
 )
 
-// Worker is working
+// Worker is a single item of work that reports its progress on the given line. The context is cancelled when the
+// queue gives up on the remaining work (see WorkQueue.FailFast). Workers that implement fmt.Stringer are named in
+// reports (e.g. when interrupted), otherwise they are referred to by the order they were added in ("#3").
 type Worker interface {
-	Work(*frame.Line)
+	Work(ctx context.Context, line *frame.Line) error
+}
+
+// WorkerFunc adapts a plain function to a Worker.
+type WorkerFunc func(ctx context.Context, line *frame.Line) error
+
+func (fn WorkerFunc) Work(ctx context.Context, line *frame.Line) error {
+	return fn(ctx, line)
+}
+
+// Result is the outcome of a single item of work. Items that never started (because the queue was cancelled)
+// have the context error.
+type Result struct {
+	Worker Worker
+	Err    error
+	// Cancelled is set when the queue was cancelled before the item finished (it was still running or never started)
+	Cancelled bool
+}
+
+// WorkError is returned by WorkQueue.Work when one or more items of work did not succeed.
+type WorkError struct {
+	Errors []error
+	Total  int
+}
+
+func (err *WorkError) Error() string {
+	if len(err.Errors) == 1 {
+		return fmt.Sprintf("1 of %d work items failed: %v", err.Total, err.Errors[0])
+	}
+	return fmt.Sprintf("%d of %d work items failed (first: %v)", len(err.Errors), err.Total, err.Errors[0])
+}
+
+// lineSource is the part of a frame.Frame that the queue draws work on
+type lineSource interface {
+	Append() (*frame.Line, error)
+	Remove(line *frame.Line) error
 }
 
 type WorkQueue struct {
 	maxConcurrent int64
-	queue         []interface{}
+	rateLimit     float64
+	queue         []*queuedWork
+	active        *scheduler
+	list          *workList
+	summary       *workSummary
+	lock          sync.Mutex
+	// FailFast cancels the context of running work and skips any work not yet started on the first failure
+	FailFast bool
+	// Summary adds a row with the overall progress of the queue as a header or footer
+	Summary SummaryPosition
+	// HandleInterrupt cancels the queue on Ctrl-C (a second Ctrl-C exits immediately)
+	HandleInterrupt bool
 }
 
 func NewWorkQueue(maxConcurrent int64) *WorkQueue {
@@ -25,12 +74,114 @@ func NewWorkQueue(maxConcurrent int64) *WorkQueue {
 	}
 }
 
-func (wq *WorkQueue) AddWork(work interface{}) {
-	wq.queue = append(wq.queue, work)
+// AddWork queues work with the default priority (zero).
+func (wq *WorkQueue) AddWork(worker Worker) error {
+	return wq.AddWorkWithPriority(worker, 0)
+}
+
+// AddWorkWithPriority queues work that starts ahead of all waiting work with a lower priority (work with the same
+// priority starts in the order it was added). Work may also be added while the queue is running, up until the
+// queue has run out of work and is finishing (which returns an error).
+func (wq *WorkQueue) AddWorkWithPriority(worker Worker, priority int) error {
+	wq.lock.Lock()
+	defer wq.lock.Unlock()
+
+	item := &queuedWork{
+		index:    len(wq.queue),
+		priority: priority,
+		worker:   worker,
+	}
+
+	if wq.active != nil {
+		if err := wq.active.push(wq.list, item); err != nil {
+			return err
+		}
+		wq.summary.queue()
+	}
+	wq.queue = append(wq.queue, item)
+	return nil
+}
+
+// SetConcurrency changes how many items of work may run at once, taking effect immediately when the queue is
+// running. Lowering the limit does not interrupt running work.
+func (wq *WorkQueue) SetConcurrency(maxConcurrent int64) {
+	wq.lock.Lock()
+	defer wq.lock.Unlock()
+
+	wq.maxConcurrent = maxConcurrent
+	if wq.active != nil {
+		wq.active.setLimit(int(maxConcurrent))
+	}
+}
+
+// SetRateLimit limits how many items of work may start per second (zero or less removes the limit), taking effect
+// immediately when the queue is running.
+func (wq *WorkQueue) SetRateLimit(perSecond float64) {
+	wq.lock.Lock()
+	defer wq.lock.Unlock()
+
+	wq.rateLimit = perSecond
+	if wq.active != nil {
+		wq.active.setRate(perSecond)
+	}
+}
+
+// Work runs all queued work (each on its own line) and blocks until every item has finished. The results are in
+// the order the work was added; the returned error is a *WorkError when any item failed.
+func (wq *WorkQueue) Work(ctx context.Context) ([]Result, error) {
+	fr, err := newWorkFrame()
+	if err != nil {
+		return nil, err
+	}
+
+	var summary *workSummary
+	if wq.Summary != SummaryNone {
+		line, err := wq.summaryLine(fr)
+		if err != nil {
+			fr.Close()
+			return nil, err
+		}
+		summary = newWorkSummary(line)
+		summary.run()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var interrupts *interruptHandler
+	if wq.HandleInterrupt {
+		interrupts = handleInterrupts(fr, cancel)
+	}
+
+	results, err := wq.run(ctx, fr, summary)
+
+	if summary != nil {
+		summary.stop()
+	}
+
+	if interrupts != nil {
+		interrupts.stop(func() string {
+			var unfinished []string
+			for idx, result := range results {
+				if result.Cancelled {
+					unfinished = append(unfinished, workName(result.Worker, idx))
+				}
+			}
+			return interruptReport(unfinished, len(results), "work items")
+		})
+	}
+
+	// only close the frame once no worker can write to it anymore
+	fr.Close()
+
+	ansi.CursorShow()
+
+	return results, err
 }
 
-func (wq *WorkQueue) Work() {
-	fr, _ := frame.New(frame.Config{
+// newWorkFrame creates the frame that queued work is drawn on.
+func newWorkFrame() (*frame.Frame, error) {
+	return frame.New(frame.Config{
 		Lines:          0,
 		HeaderRows:     0,
 		FooterRows:     0,
@@ -38,24 +189,136 @@ func (wq *WorkQueue) Work() {
 		PositionPolicy: frame.PolicyFloatForward,
 		ManualDraw:     false,
 	})
+}
+
+func (wq *WorkQueue) summaryLine(fr *frame.Frame) (*frame.Line, error) {
+	if wq.Summary == SummaryHeader {
+		return fr.AppendHeader()
+	}
+	return fr.AppendFooter()
+}
 
-	// worker pool
-	ctx := context.TODO()
-	sem := semaphore.NewWeighted(wq.maxConcurrent)
+func (wq *WorkQueue) run(ctx context.Context, lines lineSource, summary *workSummary) ([]Result, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
+	wq.lock.Lock()
+	sched := newScheduler(int(wq.maxConcurrent), wq.rateLimit)
+	list := &workList{}
 	for _, item := range wq.queue {
-		worker, _ := item.(Worker)
-		sem.Acquire(ctx, 1)
-		line, _ := fr.Append()
-		jotFunc := func(userFunc func(line *frame.Line), line *frame.Line) {
-			defer sem.Release(1)
-			userFunc(line)
-			fr.Remove(line)
+		sched.push(list, item)
+		summary.queue()
+	}
+	wq.active = sched
+	wq.list = list
+	wq.summary = summary
+	wq.lock.Unlock()
+
+	stop := make(chan struct{})
+	go sched.wake(ctx, stop)
+
+	outcomes := runList(ctx, cancel, sched, list, false, lines, summary, wq.FailFast)
+	close(stop)
+
+	wq.lock.Lock()
+	wq.active = nil
+	wq.list = nil
+	wq.summary = nil
+	queue := wq.queue
+	wq.lock.Unlock()
+
+	return collectResults(queue, outcomes)
+}
+
+// workName refers to the given work in reports (see Worker).
+func workName(worker Worker, index int) string {
+	if named, ok := worker.(fmt.Stringer); ok {
+		return named.String()
+	}
+	return fmt.Sprintf("#%d", index+1)
+}
+
+// workOutcome is how a single item of work ended
+type workOutcome struct {
+	err       error
+	cancelled bool
+}
+
+// schedulerKey is the context key that gives running work access to the scheduler of its queue
+type schedulerKey struct{}
+
+// runList runs work from the list until it is exhausted (or the context is cancelled) and returns the outcome of
+// each item. Work that was still pending on cancellation gets the context error.
+func runList(ctx context.Context, cancel func(), sched *scheduler, list *workList, child bool, lines lineSource, summary *workSummary, failFast bool) map[*queuedWork]workOutcome {
+	workCtx := context.WithValue(ctx, schedulerKey{}, sched)
+
+	var lock sync.Mutex
+	outcomes := make(map[*queuedWork]workOutcome)
+	setResult := func(item *queuedWork, err error, cancelled bool) {
+		lock.Lock()
+		outcomes[item] = workOutcome{err: err, cancelled: cancelled}
+		lock.Unlock()
+	}
+
+	var wg sync.WaitGroup
+	for {
+		item, ok := sched.next(ctx, list, child)
+		if !ok {
+			break
+		}
+
+		line, err := lines.Append()
+		if err != nil {
+			setResult(item, err, false)
+			summary.skip()
+			sched.done(list)
+			continue
 		}
-		go jotFunc(worker.Work, line)
+		summary.started()
+
+		wg.Add(1)
+		go func(item *queuedWork, line *frame.Line) {
+			defer wg.Done()
+			defer sched.done(list)
+
+			err := item.worker.Work(workCtx, line)
+			// whatever the worker returned, it did not get to finish if the queue was cancelled in the meantime
+			setResult(item, err, ctx.Err() != nil)
+			if err != nil && failFast {
+				cancel()
+			}
+			lines.Remove(line)
+			summary.finished(err)
+		}(item, line)
 	}
 
-	fr.Close()
+	wg.Wait()
 
-	ansi.CursorShow()
+	// anything still pending was cancelled before it could start
+	for _, item := range sched.drain(list) {
+		setResult(item, ctx.Err(), true)
+		summary.skip()
+	}
+
+	return outcomes
+}
+
+// collectResults orders the outcome of each item of work by the order it was added.
+func collectResults(queue []*queuedWork, outcomes map[*queuedWork]workOutcome) ([]Result, error) {
+	results := make([]Result, len(queue))
+	var failures []error
+	for idx, item := range queue {
+		results[idx] = Result{
+			Worker:    item.worker,
+			Err:       outcomes[item].err,
+			Cancelled: outcomes[item].cancelled,
+		}
+		if results[idx].Err != nil {
+			failures = append(failures, results[idx].Err)
+		}
+	}
+	if len(failures) > 0 {
+		return results, &WorkError{Errors: failures, Total: len(results)}
+	}
+	return results, nil
 }
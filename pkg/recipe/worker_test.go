@@ -0,0 +1,124 @@
+package recipe
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wagoodman/jotframe/pkg/frame"
+)
+
+// testLines hands out standalone lines that are not drawn anywhere
+type testLines struct {
+	lock    sync.Mutex
+	events  chan frame.ScreenEvent
+	active  int
+	removed int
+}
+
+func newTestLines() *testLines {
+	lines := &testLines{events: make(chan frame.ScreenEvent, 100)}
+	go func() {
+		for range lines.events {
+		}
+	}()
+	return lines
+}
+
+func (lines *testLines) Append() (*frame.Line, error) {
+	lines.lock.Lock()
+	defer lines.lock.Unlock()
+	lines.active++
+	return frame.NewLine(0, lines.events), nil
+}
+
+func (lines *testLines) Remove(*frame.Line) error {
+	lines.lock.Lock()
+	defer lines.lock.Unlock()
+	lines.active--
+	lines.removed++
+	return nil
+}
+
+func Test_WorkQueue_run(t *testing.T) {
+	lines := newTestLines()
+	wq := NewWorkQueue(2)
+
+	var running, peak int64
+	failure := fmt.Errorf("boom")
+	for idx := 0; idx < 6; idx++ {
+		idx := idx
+		wq.AddWork(WorkerFunc(func(ctx context.Context, line *frame.Line) error {
+			current := atomic.AddInt64(&running, 1)
+			defer atomic.AddInt64(&running, -1)
+			for {
+				observed := atomic.LoadInt64(&peak)
+				if current <= observed || atomic.CompareAndSwapInt64(&peak, observed, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			if idx == 4 {
+				return failure
+			}
+			return nil
+		}))
+	}
+
+	results, err := wq.run(context.Background(), lines, nil)
+
+	if peak > 2 {
+		t.Errorf("WorkQueue.run(): expected at most 2 concurrent workers, got %d", peak)
+	}
+
+	workErr, ok := err.(*WorkError)
+	if !ok || len(workErr.Errors) != 1 || workErr.Errors[0] != failure || workErr.Total != 6 {
+		t.Fatalf("WorkQueue.run(): expected a single failure, got %v", err)
+	}
+
+	for idx, result := range results {
+		if (idx == 4) != (result.Err != nil) {
+			t.Errorf("WorkQueue.run(): unexpected result %d: %v", idx, result.Err)
+		}
+	}
+
+	if lines.active != 0 || lines.removed != 6 {
+		t.Errorf("WorkQueue.run(): expected all lines to be removed (active=%d removed=%d)", lines.active, lines.removed)
+	}
+}
+
+func Test_WorkQueue_FailFast(t *testing.T) {
+	lines := newTestLines()
+	wq := NewWorkQueue(1)
+	wq.FailFast = true
+
+	var started int64
+	for idx := 0; idx < 4; idx++ {
+		idx := idx
+		wq.AddWork(WorkerFunc(func(ctx context.Context, line *frame.Line) error {
+			atomic.AddInt64(&started, 1)
+			if idx == 1 {
+				return fmt.Errorf("boom")
+			}
+			return nil
+		}))
+	}
+
+	results, err := wq.run(context.Background(), lines, nil)
+	if err == nil {
+		t.Fatalf("WorkQueue.run(): expected an error")
+	}
+
+	if started != 2 {
+		t.Errorf("WorkQueue.run(): expected work to stop after the failure, but %d items started", started)
+	}
+
+	for _, result := range results[2:] {
+		if result.Err != context.Canceled {
+			t.Errorf("WorkQueue.run(): expected skipped work to be cancelled, got %v", result.Err)
+		}
+	}
+}